@@ -4,11 +4,11 @@ package check
 
 const aliasSkippedFrame = 1
 
-func (c *C) runObtainedExpectedAlias(funcName string, obtained, expected any, checker Checker, args any) bool {
-	comf := commentArgs(args)
+func (c *C) runObtainedExpectedAlias(funcName string, obtained, expected any, checker Checker, args []any) bool {
+	comf := commentArgs(args...)
 
 	if comf != nil {
-		if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, expected, commentArgs(args)) {
+		if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, expected, comf) {
 			c.stopNow()
 			return false
 		}
@@ -20,11 +20,11 @@ func (c *C) runObtainedExpectedAlias(funcName string, obtained, expected any, ch
 	return true
 }
 
-func (c *C) runObtainedAlias(funcName string, obtained any, checker Checker, args any) bool {
-	comf := commentArgs(args)
+func (c *C) runObtainedAlias(funcName string, obtained any, checker Checker, args []any) bool {
+	comf := commentArgs(args...)
 
 	if comf != nil {
-		if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, commentArgs(args)) {
+		if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, comf) {
 			c.stopNow()
 			return false
 		}
@@ -36,6 +36,22 @@ func (c *C) runObtainedAlias(funcName string, obtained any, checker Checker, arg
 	return true
 }
 
+func (c *C) runObtainedTwoArgsAlias(funcName string, obtained, arg1, arg2 any, checker Checker, args []any) bool {
+	comf := commentArgs(args...)
+
+	if comf != nil {
+		if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, arg1, arg2, comf) {
+			c.stopNow()
+			return false
+		}
+	} else if !c.internalCheck(aliasSkippedFrame, funcName, obtained, checker, arg1, arg2) {
+		c.stopNow()
+		return false
+	}
+
+	return true
+}
+
 func (c *C) Contains(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("Contains", obtained, expected, Contains, args)
 }
@@ -52,6 +68,21 @@ func (c *C) NotDeepEquals(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("NotDeepEquals", obtained, expected, Not(DeepEquals), args)
 }
 
+// DeepEqualsWith takes the DeepOption values among args as options of the
+// DeepEqualsWith checker, and the other args as the comment.
+func (c *C) DeepEqualsWith(obtained, expected any, args ...any) bool {
+	var options []DeepOption
+	var comment []any
+	for _, arg := range args {
+		if option, ok := arg.(DeepOption); ok {
+			options = append(options, option)
+		} else {
+			comment = append(comment, arg)
+		}
+	}
+	return c.runObtainedAlias("DeepEqualsWith", obtained, DeepEqualsWith(expected, options...), comment)
+}
+
 func (c *C) Equals(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("Equals", obtained, expected, Equals, args)
 }
@@ -77,6 +108,58 @@ func (c *C) NotEqualsMore(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("NotEqualsMore", obtained, expected, Not(EqualsMore), args)
 }
 
+func (c *C) ElementsMatch(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("ElementsMatch", obtained, expected, ElementsMatch, args)
+}
+
+func (c *C) IsSubsetOf(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("IsSubsetOf", obtained, expected, IsSubsetOf, args)
+}
+
+func (c *C) IsSupersetOf(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("IsSupersetOf", obtained, expected, IsSupersetOf, args)
+}
+
+func (c *C) HasKeys(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("HasKeys", obtained, expected, HasKeys, args)
+}
+
+func (c *C) HasValues(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("HasValues", obtained, expected, HasValues, args)
+}
+
+func (c *C) InDelta(obtained, expected, delta any, args ...any) bool {
+	return c.runObtainedTwoArgsAlias("InDelta", obtained, expected, delta, InDelta, args)
+}
+
+func (c *C) InEpsilon(obtained, expected, epsilon any, args ...any) bool {
+	return c.runObtainedTwoArgsAlias("InEpsilon", obtained, expected, epsilon, InEpsilon, args)
+}
+
+func (c *C) WithinULP(obtained, expected, ulps any, args ...any) bool {
+	return c.runObtainedTwoArgsAlias("WithinULP", obtained, expected, ulps, WithinULP, args)
+}
+
+func (c *C) TimeEquals(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("TimeEquals", obtained, expected, TimeEquals, args)
+}
+
+func (c *C) WithinDuration(obtained, expected, tolerance any, args ...any) bool {
+	return c.runObtainedTwoArgsAlias("WithinDuration", obtained, expected, tolerance, WithinDuration, args)
+}
+
+func (c *C) IsBefore(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("IsBefore", obtained, expected, IsBefore, args)
+}
+
+func (c *C) IsAfter(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("IsAfter", obtained, expected, IsAfter, args)
+}
+
+func (c *C) DurationBetween(obtained, min, max any, args ...any) bool {
+	return c.runObtainedTwoArgsAlias("DurationBetween", obtained, min, max, DurationBetween, args)
+}
+
 func (c *C) ErrorMatches(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("ErrorMatches", obtained, expected, ErrorMatches, args)
 }
@@ -109,6 +192,18 @@ func (c *C) ErrorIs(obtained, expected any, args ...any) bool {
 	return c.runObtainedExpectedAlias("ErrorIs", obtained, expected, ErrorIs, args)
 }
 
+func (c *C) JSONEquals(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("JSONEquals", obtained, expected, JSONEquals, args)
+}
+
+func (c *C) JSONContains(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("JSONContains", obtained, expected, JSONContains, args)
+}
+
+func (c *C) YAMLEquals(obtained, expected any, args ...any) bool {
+	return c.runObtainedExpectedAlias("YAMLEquals", obtained, expected, YAMLEquals, args)
+}
+
 func (c *C) IsFalse(obtained any, args ...any) bool {
 	return c.runObtainedAlias("IsFalse", obtained, IsFalse, args)
 }
@@ -0,0 +1,59 @@
+package check
+
+import "cmp"
+
+// File contents type-safe aliases for Checker over Assert. Both values must
+// have the same type, so mistakes such as comparing an int64 with an untyped
+// constant are caught by the compiler instead of failing at run time.
+
+// Equal stops the test unless obtained == expected.
+func Equal[T comparable](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("Equal", obtained, expected, Equals, args)
+}
+
+// NotEqual stops the test if obtained == expected.
+func NotEqual[T comparable](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("NotEqual", obtained, expected, NotEquals, args)
+}
+
+// DeepEqual stops the test unless obtained and expected are deeply equal.
+func DeepEqual[T any](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("DeepEqual", obtained, expected, DeepEquals, args)
+}
+
+// NotDeepEqual stops the test if obtained and expected are deeply equal.
+func NotDeepEqual[T any](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("NotDeepEqual", obtained, expected, Not(DeepEquals), args)
+}
+
+// Less stops the test unless obtained < expected.
+func Less[T cmp.Ordered](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("Less", obtained, expected, LessThan, args)
+}
+
+// LessOrEqual stops the test unless obtained <= expected.
+func LessOrEqual[T cmp.Ordered](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("LessOrEqual", obtained, expected, LessOrEqualThan, args)
+}
+
+// Greater stops the test unless obtained > expected.
+func Greater[T cmp.Ordered](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("Greater", obtained, expected, MoreThan, args)
+}
+
+// GreaterOrEqual stops the test unless obtained >= expected.
+func GreaterOrEqual[T cmp.Ordered](c *C, obtained, expected T, args ...any) bool {
+	return c.runObtainedExpectedAlias("GreaterOrEqual", obtained, expected, MoreOrEqualThan, args)
+}
+
+// ElementsOf stops the test unless obtained is one of the elements of
+// expected.
+func ElementsOf[T comparable](c *C, obtained T, expected []T, args ...any) bool {
+	return c.runObtainedExpectedAlias("ElementsOf", obtained, expected, Contains, args)
+}
+
+// NotElementsOf stops the test if obtained is one of the elements of
+// expected.
+func NotElementsOf[T comparable](c *C, obtained T, expected []T, args ...any) bool {
+	return c.runObtainedExpectedAlias("NotElementsOf", obtained, expected, NotContains, args)
+}
@@ -0,0 +1,81 @@
+package check_test
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/iostrovok/check"
+)
+
+type GenericAliasSuite struct{}
+
+var _ = check.Suite(&GenericAliasSuite{})
+
+type celsius float64
+
+func (s *GenericAliasSuite) TestEqual(c *check.C) {
+	check.Equal(c, int64(1), 1)
+	check.Equal(c, "abc", "abc", "test syntax")
+}
+
+func (s *GenericAliasSuite) TestNotEqual(c *check.C) {
+	check.NotEqual(c, int64(1), 2)
+	check.NotEqual(c, "abc", "abd", "test syntax")
+}
+
+func (s *GenericAliasSuite) TestDeepEqual(c *check.C) {
+	check.DeepEqual(c, []int{42}, []int{42})
+	check.DeepEqual(c, map[string]int{"a": 1}, map[string]int{"a": 1}, "test syntax")
+}
+
+func (s *GenericAliasSuite) TestNotDeepEqual(c *check.C) {
+	check.NotDeepEqual(c, []int{42}, []int{43})
+	check.NotDeepEqual(c, []int{42}, []int{42, 1}, "test syntax")
+}
+
+func (s *GenericAliasSuite) TestOrdered(c *check.C) {
+	check.Less(c, uint8(1), 2)
+	check.Less(c, "abc", "abd", "test syntax")
+	check.LessOrEqual(c, celsius(0.1), 0.1)
+	check.Greater(c, 10, -8)
+	check.Greater(c, "b", "a", "test syntax")
+	check.GreaterOrEqual(c, int32(3), 3)
+}
+
+func (s *GenericAliasSuite) TestElementsOf(c *check.C) {
+	check.ElementsOf(c, 42, []int{1, 42})
+	check.ElementsOf(c, "a", []string{"a"}, "test syntax")
+	check.NotElementsOf(c, 43, []int{1, 42})
+	check.NotElementsOf(c, "b", []string{"a"}, "test syntax")
+}
+
+type GenericAliasHelper struct{}
+
+func (s *GenericAliasHelper) TestEquals(c *check.C) {
+	c.Equals(int64(1), int64(2), "some comment")
+}
+
+func (s *GenericAliasHelper) TestEqual(c *check.C) {
+	check.Equal(c, int64(1), 2, "some comment")
+	c.Log("not reached")
+}
+
+func (s *GenericAliasSuite) TestFailureOutput(c *check.C) {
+	output := String{}
+	result := check.Run(&GenericAliasHelper{}, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 2 FAILED")
+	c.Check(result.Tests, check.HasLen, 2)
+
+	// Both fail with the same report, but for the source line.
+	logs := map[string]string{}
+	for _, test := range result.Tests {
+		logs[test.Method] = regexp.MustCompile(`(?m)^.*:\d+:\n.*\n`).ReplaceAllString(test.Log, "")
+	}
+	c.Check(logs["TestEqual"], check.Equals, logs["TestEquals"])
+	c.Check(logs["TestEqual"], check.Equals, "... obtained int64 = 1\n... expected int64 = 2\n... some comment\n\n")
+}
+
+func (s *GenericAliasSuite) TestErrorsStayAny(c *check.C) {
+	err := errors.New("boom")
+	check.Equal[error](c, err, err)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/iostrovok/check"
 )
@@ -149,6 +150,58 @@ func (s *AliasSuite) TestIsTrueAlias(c *check.C) {
 	c.IsTrue(true, "test syntax")
 }
 
+func (s *AliasSuite) TestElementsMatchAlias(c *check.C) {
+	c.ElementsMatch([]int{1, 2, 2}, []int{2, 1, 2})
+	c.ElementsMatch([2]string{"a", "b"}, []string{"b", "a"}, "test syntax")
+}
+
+func (s *AliasSuite) TestSubsetAliases(c *check.C) {
+	c.IsSubsetOf([]int{1, 3}, []int{1, 2, 3})
+	c.IsSupersetOf(map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1}, "test syntax")
+	c.HasKeys(map[string]int{"a": 1, "b": 2}, []string{"b"})
+	c.HasValues(map[string]int{"a": 1, "b": 2}, []int{2, 1})
+}
+
+func (s *AliasSuite) TestToleranceAliases(c *check.C) {
+	a, b := 0.1, 0.2
+	c.InDelta(a+b, 0.3, 1e-9)
+	c.InEpsilon(1010, 1000, 0.01, "test syntax")
+	c.WithinULP(a+b, 0.3, 1)
+}
+
+func (s *AliasSuite) TestTimeAliases(c *check.C) {
+	now := time.Now()
+	c.TimeEquals(now, now.UTC().Round(0))
+	c.WithinDuration(now.Add(time.Millisecond), now, time.Second, "test syntax")
+	c.IsBefore(now, now.Add(time.Nanosecond))
+	c.IsAfter(now.Add(time.Nanosecond), now)
+	c.DurationBetween(time.Second, time.Millisecond, time.Minute)
+}
+
+func (s *AliasSuite) TestDeepEqualsWithAlias(c *check.C) {
+	type item struct {
+		ID   int
+		Name string
+	}
+	c.DeepEqualsWith([]item{{1, "a"}}, []item{{2, "a"}}, check.IgnoreFields("ID"))
+	c.DeepEqualsWith([]item{{1, "a"}}, []item{{1, "b"}}, "test syntax", check.IgnoreFields("Name"))
+}
+
+func (s *AliasSuite) TestJSONEqualsAlias(c *check.C) {
+	c.JSONEquals(`{"a": 1, "b": [2]}`, `{"b":[2],"a":1}`)
+	c.JSONEquals([]byte(`{"a": 1}`), map[string]int{"a": 1}, "test syntax")
+}
+
+func (s *AliasSuite) TestJSONContainsAlias(c *check.C) {
+	c.JSONContains(`{"a": 1, "b": [2]}`, `{"a": 1}`)
+	c.JSONContains(`{"a": 1, "b": [2]}`, `{"b": [2]}`, "test syntax")
+}
+
+func (s *AliasSuite) TestYAMLEqualsAlias(c *check.C) {
+	c.YAMLEquals("a: 1\nb: [2]\n", "b:\n  - 2\na: 1\n")
+	c.YAMLEquals("a: 1\n", `{"a": 1}`, "test syntax")
+}
+
 func (s *AliasSuite) TestMoreOrEqualThanAlias(c *check.C) {
 	c.MoreOrEqualThan(0.1, 0.1)
 	c.MoreOrEqualThan(0.1, 0.1, "test syntax")
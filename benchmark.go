@@ -115,6 +115,28 @@ func (c *C) mbPerSec() float64 {
 	return (float64(c.bytes) * float64(c.N) / 1e6) / c.duration.Seconds()
 }
 
+// BenchmarkResult holds the numbers measured by a benchmark.
+type BenchmarkResult struct {
+	N           int
+	Duration    time.Duration
+	NsPerOp     int64
+	MBPerSec    float64
+	AllocsPerOp int64 // Only with RunConf.BenchmarkMem.
+	BytesPerOp  int64 // Only with RunConf.BenchmarkMem.
+}
+
+func (c *C) benchmarkResult() *BenchmarkResult {
+	if c.N <= 0 {
+		return nil
+	}
+	r := &BenchmarkResult{N: c.N, Duration: c.duration, NsPerOp: c.nsPerOp(), MBPerSec: c.mbPerSec()}
+	if c.benchMem {
+		r.AllocsPerOp = int64(c.netAllocs) / int64(c.N)
+		r.BytesPerOp = int64(c.netBytes) / int64(c.N)
+	}
+	return r
+}
+
 func (c *C) timerString() string {
 	if c.N <= 0 {
 		return fmt.Sprintf("%3.3fs", float64(c.duration.Nanoseconds())/1e9)
@@ -7,6 +7,7 @@ package check
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -33,6 +34,7 @@ import (
 const (
 	fixtureKd = iota
 	testKd
+	subtestKd
 )
 
 type funcKind int
@@ -44,6 +46,7 @@ const (
 	panickedSt
 	fixturePanickedSt
 	missedSt
+	timedOutSt
 )
 
 type funcStatus uint32
@@ -86,7 +89,7 @@ type C struct {
 	testName  string
 	_status   funcStatus
 	logb      *logger
-	logw      io.Writer
+	logw      *outputWriter
 	done      chan *C
 	reason    string
 	mustFail  bool
@@ -96,7 +99,38 @@ type C struct {
 	testingT  *testing.T
 	timer
 
-	formatter    formatters.Formatter
+	parallel   *parallelGate
+	parallelCh chan bool
+	isParallel bool
+	_slot      uint32
+	sharedUse  string // Setenv or Chdir, once used by the test.
+
+	deadline  time.Time
+	watchdog  *time.Timer
+	_finished uint32
+	_tornDown uint32
+	fixtureM  sync.Mutex
+	fixture   *C // The last fixture call run on behalf of the test.
+
+	failuresM sync.Mutex
+	failures  []string
+
+	attempt  int  // How many times the test was retried.
+	retrying bool // Failed, but it's going to be retried.
+	quiet    bool // Only reported if it has a problem.
+
+	runner   *suiteRunner
+	subtests map[string]int // Names of subtests run so far.
+
+	cleanups        *cleanupStack
+	fixtureCleanups *cleanupStack // Registered by SetUpTest, run after TearDownTest.
+	filesDir        string        // Where TempFile creates files.
+
+	ctxM sync.Mutex // Also guards deadline, which moves in Parallel.
+	ctx  context.Context
+
+	snapshots int // How many times MatchSnapshot was called.
+
 	formatPrefix string
 }
 
@@ -112,6 +146,28 @@ func (c *C) stopNow() {
 	runtime.Goexit()
 }
 
+// finish marks the call as finished, and returns whether it was the first
+// to do so. A call which times out is finished on its behalf while the
+// goroutine running it may be still alive.
+func (c *C) finish() bool {
+	return atomic.CompareAndSwapUint32(&c._finished, 0, 1)
+}
+
+// elapsed returns how long the call ran.  The timer of a call which timed
+// out is left alone, since its goroutine may be still running.
+func (c *C) elapsed() time.Duration {
+	if c.status() == timedOutSt {
+		return time.Since(c.startTime)
+	}
+	return c.duration
+}
+
+// startTearDown returns whether the caller must run the final TearDownTest
+// of the call, which happens only once even if the call times out.
+func (c *C) startTearDown() bool {
+	return atomic.CompareAndSwapUint32(&c._tornDown, 0, 1)
+}
+
 // logger is a concurrency safe byte.Buffer
 type logger struct {
 	sync.Mutex
@@ -136,6 +192,332 @@ func (l *logger) String() string {
 	return l.writer.String()
 }
 
+// -----------------------------------------------------------------------
+// Scheduling of tests marked with C.Parallel.
+
+type parallelGate struct {
+	slots   chan bool // Bounds how many parallel tests run at once.
+	release chan bool // Closed once all sequential tests are done.
+}
+
+func newParallelGate(n int) *parallelGate {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	return &parallelGate{slots: make(chan bool, n), release: make(chan bool)}
+}
+
+// Parallel signals that the running test may be run in parallel with
+// other tests of the same suite which also call Parallel. The test is
+// paused until all the sequential tests in the suite have finished, and
+// is then resumed as soon as fewer than RunConf.Parallel parallel tests
+// are running. SetUpTest has already run at this point, and TearDownTest
+// runs as usual once the test is over, so fixtures shared between parallel
+// tests must be safe for concurrent use.  Parallel tests aren't retried
+// when they fail, whatever RunConf.Retries says.
+func (c *C) Parallel() {
+	if c.kind != testKd || c.parallel == nil {
+		panic("Parallel must be called from within a test method")
+	}
+	if c.N > 0 {
+		panic("Parallel is not supported by benchmarks")
+	}
+	if c.isParallel {
+		panic("Parallel called multiple times")
+	}
+	if c.sharedUse != "" {
+		panic("Parallel can't be used by tests calling " + c.sharedUse)
+	}
+	if c.watchdog != nil && !c.watchdog.Stop() {
+		c.stopNow() // Timed out already.
+	}
+	remaining := time.Until(c.deadline)
+	c.isParallel = true
+	c.StopTimer()
+	c.parallelCh <- true
+	<-c.parallel.release
+	c.parallel.slots <- true
+	atomic.StoreUint32(&c._slot, 1)
+	if c.watchdog != nil {
+		c.ctxM.Lock()
+		c.deadline = time.Now().Add(remaining)
+		c.ctxM.Unlock()
+		c.watchdog.Reset(remaining)
+	}
+	c.StartTimer()
+}
+
+func (c *C) releaseSlot() {
+	if atomic.CompareAndSwapUint32(&c._slot, 1, 0) {
+		<-c.parallel.slots
+	}
+}
+
+// -----------------------------------------------------------------------
+// Subtests.
+
+// Run runs f as a subtest of the running test, named after the test and
+// the given name, as in "Suite.TestX/name".  The subtest has its own log,
+// status and timer, and it's reported on its own, but it isn't counted in
+// the result of the run.  A failure of the subtest marks the running test
+// as failed without stopping it, so other subtests still run.  Subtests may
+// be selected with a filter such as "Suite.TestX/name".  Run returns
+// whether the subtest succeeded.
+func (c *C) Run(name string, f func(c *C)) bool {
+	if c.kind == fixtureKd || c.runner == nil {
+		panic("Run must be called from within a test method")
+	}
+	if c.N > 0 {
+		panic("Run is not supported by benchmarks")
+	}
+	name = c.subtestName(name)
+	path := c.subtestPath()
+	if filter := c.runner.subFilters; len(filter) > strings.Count(path, "/") {
+		if !filter[strings.Count(path, "/")].MatchString(name) {
+			return true
+		}
+	}
+
+	sub := c.runner.forkCall(c.method, subtestKd, c.testName+"/"+name, nil, nil, func(sub *C) {
+		defer sub.cleanups.run()
+		if !c.deadline.IsZero() {
+			// Subtests time out along with their test.
+			sub.deadline = c.deadline
+			sub.watchdog = time.AfterFunc(time.Until(sub.deadline), func() {
+				c.runner.timeoutCall(sub)
+			})
+		}
+		sub.ResetTimer()
+		sub.StartTimer()
+		defer sub.StopTimer()
+		f(sub)
+	})
+	<-sub.done
+	switch sub.status() {
+	case succeededSt, skippedSt:
+		return true
+	}
+	c.Fail()
+	return false
+}
+
+// subtestName returns a unique name for a subtest of c, with spaces
+// replaced just like the testing package does.
+func (c *C) subtestName(name string) string {
+	name = strings.Join(strings.Fields(name), "_")
+	if c.subtests == nil {
+		c.subtests = make(map[string]int)
+	}
+	n := c.subtests[name]
+	c.subtests[name]++
+	if n > 0 {
+		name = fmt.Sprintf("%s#%02d", name, n)
+	}
+	return name
+}
+
+// subtestPath returns the path of c within its test, as in "/a/b", which
+// is empty unless c is a subtest.
+func (c *C) subtestPath() string {
+	if c.kind != subtestKd {
+		return ""
+	}
+	return strings.TrimPrefix(c.testName, c.method.String())
+}
+
+// -----------------------------------------------------------------------
+// Cleanup functions registered by tests and fixtures.
+
+type cleanupStack struct {
+	sync.Mutex
+	funcs   []func()
+	cancels []context.CancelCauseFunc // Of contexts given by C.Context.
+}
+
+// cancel cancels the contexts of the stack with the given cause.
+func (s *cleanupStack) cancel(cause error) {
+	s.Lock()
+	cancels := s.cancels
+	s.cancels = nil
+	s.Unlock()
+	for _, cancel := range cancels {
+		cancel(cause)
+	}
+}
+
+func (s *cleanupStack) push(f func()) {
+	s.Lock()
+	s.funcs = append(s.funcs, f)
+	s.Unlock()
+}
+
+func (s *cleanupStack) pop() func() {
+	s.Lock()
+	defer s.Unlock()
+	if len(s.funcs) == 0 {
+		return nil
+	}
+	f := s.funcs[len(s.funcs)-1]
+	s.funcs = s.funcs[:len(s.funcs)-1]
+	return f
+}
+
+func (s *cleanupStack) empty() bool {
+	s.Lock()
+	defer s.Unlock()
+	return len(s.funcs) == 0 && len(s.cancels) == 0
+}
+
+// run cancels the contexts of the stack, and then calls the registered
+// functions in reverse order.  The remaining ones still run when one of
+// them panics or stops the goroutine.
+func (s *cleanupStack) run() {
+	s.cancel(nil)
+	f := s.pop()
+	if f == nil {
+		return
+	}
+	defer s.run()
+	f()
+}
+
+// Cleanup registers a function to be called once the test or fixture is
+// over, even if it failed or panicked.  Functions are called in reverse
+// order of registration.  Functions registered by SetUpSuite and SetUpTest
+// are only called after TearDownSuite and TearDownTest, respectively.
+func (c *C) Cleanup(f func()) {
+	c.cleanups.push(f)
+}
+
+// Context returns a context which is cancelled once the test or fixture is
+// over, even if it failed or panicked, right before its Cleanup functions
+// are called.  Contexts of SetUpSuite and SetUpTest are only cancelled
+// after TearDownSuite and TearDownTest, respectively.  The context of a
+// test has the deadline of the test, if any, and it expires with
+// context.DeadlineExceeded when the test times out.
+func (c *C) Context() context.Context {
+	c.ctxM.Lock()
+	defer c.ctxM.Unlock()
+	if c.ctx == nil {
+		var cancel context.CancelCauseFunc
+		if c.deadline.IsZero() {
+			c.ctx, cancel = context.WithCancelCause(context.Background())
+		} else {
+			ctx := &callContext{c: c, done: make(chan struct{})}
+			c.ctx, cancel = ctx, ctx.cancel
+		}
+		c.cleanups.Lock()
+		c.cleanups.cancels = append(c.cleanups.cancels, cancel)
+		c.cleanups.Unlock()
+	}
+	return c.ctx
+}
+
+// callContext is the context of a call with a deadline.  Rather than
+// expiring on its own, it expires once the call is reported as timed out,
+// and its deadline moves along with the one of the call when the test is
+// paused by Parallel.
+type callContext struct {
+	c    *C
+	done chan struct{}
+	err  error
+}
+
+func (ctx *callContext) Deadline() (deadline time.Time, ok bool) {
+	ctx.c.ctxM.Lock()
+	defer ctx.c.ctxM.Unlock()
+	return ctx.c.deadline, true
+}
+
+func (ctx *callContext) Done() <-chan struct{} {
+	ctx.c.ctxM.Lock()
+	defer ctx.c.ctxM.Unlock()
+	return ctx.done
+}
+
+func (ctx *callContext) Err() error {
+	ctx.c.ctxM.Lock()
+	defer ctx.c.ctxM.Unlock()
+	return ctx.err
+}
+
+func (ctx *callContext) Value(key any) any {
+	return nil
+}
+
+// cancel cancels the context, which expires if the cause is
+// context.DeadlineExceeded.
+func (ctx *callContext) cancel(cause error) {
+	ctx.c.ctxM.Lock()
+	defer ctx.c.ctxM.Unlock()
+	if ctx.err != nil {
+		return
+	}
+	ctx.err = context.Canceled
+	if errors.Is(cause, context.DeadlineExceeded) {
+		ctx.err = context.DeadlineExceeded
+	}
+	close(ctx.done)
+}
+
+// Deadline returns when the running test times out, as set with
+// RunConf.TestTimeout, or else when the go test binary times out.
+func (c *C) Deadline() (deadline time.Time, ok bool) {
+	c.ctxM.Lock()
+	deadline = c.deadline
+	c.ctxM.Unlock()
+	if !deadline.IsZero() {
+		return deadline, true
+	}
+	if c.testingT != nil {
+		return c.testingT.Deadline()
+	}
+	return time.Time{}, false
+}
+
+// Setenv sets an environment variable, restoring its previous value with
+// Cleanup.  It can't be used by parallel tests, since the environment is
+// shared by the whole process, and Parallel panics once it's used.
+func (c *C) Setenv(key, value string) {
+	if c.isParallel {
+		panic("Setenv can't be used by parallel tests")
+	}
+	c.sharedUse = "Setenv"
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		c.Fatalf("Cannot set environment variable %s: %v", key, err)
+	}
+	c.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
+
+// Chdir changes the working directory, restoring the previous one with
+// Cleanup.  It can't be used by parallel tests, since the working directory
+// is shared by the whole process, and Parallel panics once it's used.
+func (c *C) Chdir(dir string) {
+	if c.isParallel {
+		panic("Chdir can't be used by parallel tests")
+	}
+	c.sharedUse = "Chdir"
+	prev, err := os.Getwd()
+	if err != nil {
+		c.Fatalf("Cannot get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		c.Fatalf("Cannot change working directory: %v", err)
+	}
+	c.Cleanup(func() {
+		if err := os.Chdir(prev); err != nil {
+			panic(fmt.Sprintf("Cannot restore working directory %s: %v", prev, err))
+		}
+	})
+}
+
 // -----------------------------------------------------------------------
 // Handling of temporary files and directories.
 
@@ -148,6 +530,30 @@ type tempDir struct {
 func (td *tempDir) newPath() string {
 	td.Lock()
 	defer td.Unlock()
+	td.create()
+	result := filepath.Join(td.path, strconv.Itoa(td.counter))
+	td.counter++
+	return result
+}
+
+// newNamedPath is like newPath, but the path is named after the given
+// name, as in "Suite.TestX-3".
+func (td *tempDir) newNamedPath(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '_' || '0' <= r && r <= '9' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, name)
+	td.Lock()
+	defer td.Unlock()
+	td.create()
+	result := filepath.Join(td.path, fmt.Sprintf("%s-%d", name, td.counter))
+	td.counter++
+	return result
+}
+
+func (td *tempDir) create() {
 	if td.path == "" {
 		var err error
 		for i := 0; i != 100; i++ {
@@ -161,9 +567,6 @@ func (td *tempDir) newPath() string {
 			panic("Couldn't create temporary directory: " + err.Error())
 		}
 	}
-	result := filepath.Join(td.path, strconv.Itoa(td.counter))
-	td.counter++
-	return result
 }
 
 func (td *tempDir) removeAll() {
@@ -187,6 +590,67 @@ func (c *C) MkDir() string {
 	return path
 }
 
+// TempDir creates a new temporary directory named after the running test,
+// which is removed with Cleanup once the test is over, unless the work
+// directory is kept (see RunConf.KeepWorkDir).
+func (c *C) TempDir() string {
+	name := c.testName
+	if name == "" {
+		// Suite fixtures.
+		name = c.method.String()
+	}
+	path := c.tempDir.newNamedPath(name)
+	if err := os.Mkdir(path, 0700); err != nil {
+		panic(fmt.Sprintf("Couldn't create temporary directory %s: %s", path, err.Error()))
+	}
+	if c.runner == nil || !c.runner.keepDir {
+		c.Cleanup(func() { os.RemoveAll(path) })
+	}
+	return path
+}
+
+// TempFile creates a new file with the given contents in a temporary
+// directory of the running test, and returns its path.  The file name is
+// made from pattern as done by os.CreateTemp.
+func (c *C) TempFile(pattern, contents string) string {
+	if c.filesDir == "" {
+		c.filesDir = c.TempDir()
+	}
+	f, err := os.CreateTemp(c.filesDir, pattern)
+	if err != nil {
+		c.Fatalf("Cannot create temporary file: %v", err)
+	}
+	_, err = f.WriteString(contents)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		c.Fatalf("Cannot write temporary file %s: %v", f.Name(), err)
+	}
+	return f.Name()
+}
+
+// WriteFiles creates a new temporary directory with TempDir, writes the
+// given files into it, and returns its path.  Files are mapped from their
+// slash-separated paths within the directory to their contents, and any
+// missing parent directories are created.
+func (c *C) WriteFiles(files map[string]string) string {
+	dir := c.TempDir()
+	for name, contents := range files {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			c.Fatalf("Cannot write file %q outside of the temporary directory", name)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			c.Fatalf("Cannot create directory for %s: %v", name, err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			c.Fatalf("Cannot write file %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
 // -----------------------------------------------------------------------
 // Low-level logging functions.
 
@@ -205,7 +669,7 @@ func (c *C) logNewLine() {
 func (c *C) writeLog(buf []byte) {
 	c.logb.Write(buf)
 	if c.logw != nil {
-		c.logw.Write(buf)
+		c.logw.WriteCallOutput(c, buf)
 	}
 }
 
@@ -317,6 +781,9 @@ func (c *C) logCaller(skip int) {
 		c.logCode(testFile, testLine)
 	}
 	c.logCode(callerFile, callerLine)
+	c.failuresM.Lock()
+	c.failures = append(c.failures, fmt.Sprintf("%s:%d", nicePath(callerFile), callerLine))
+	c.failuresM.Unlock()
 }
 
 func (c *C) logCode(path string, line int) {
@@ -369,6 +836,19 @@ func (c *C) logArgPanic(method *methodType, expectedType string) {
 		niceFuncName(method.PC()), expectedType)
 }
 
+func (c *C) logGoroutines() {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	c.logf("... Goroutines:\n\n%s", buf)
+}
+
 // -----------------------------------------------------------------------
 // Some simple formatting helpers.
 
@@ -421,6 +901,28 @@ func niceFuncName(pc uintptr) string {
 	return "<unknown function>"
 }
 
+// niceFuncPackage returns the import path of the package defining the
+// function, with external test packages reported as the package tested.
+func niceFuncPackage(pc uintptr) string {
+	function := runtime.FuncForPC(pc)
+	if function == nil {
+		return ""
+	}
+	return funcPackage(function.Name())
+}
+
+// funcPackage returns the import path of the package out of the symbol
+// name of a function.  Dots in the last element of the path are escaped
+// in symbol names, as in "gopkg.in/foo%2ev1.Func".
+func funcPackage(name string) string {
+	dir, base := path.Split(name)
+	if i := strings.Index(base, "."); i > 0 {
+		base = base[:i]
+	}
+	base = strings.ReplaceAll(base, "%2e", ".")
+	return strings.TrimSuffix(dir+base, "_test")
+}
+
 // -----------------------------------------------------------------------
 // CheckTestResult tracker to aggregate call results.
 
@@ -431,9 +933,56 @@ type CheckTestResult struct {
 	Panicked         int
 	FixturePanicked  int
 	ExpectedFailures int
-	Missed           int    // Not even tried to run, related to a panic in the fixture.
-	RunError         error  // Houston, we've got a problem.
-	WorkDir          string // If KeepWorkDir is true
+	Missed           int          // Not even tried to run, related to a panic in the fixture.
+	TimedOut         int          // Abandoned after running for longer than the test timeout.
+	Flaky            int          // Passed only after being retried.
+	RunError         error        // Houston, we've got a problem.
+	WorkDir          string       // If KeepWorkDir is true
+	Tests            []TestResult // In the order they were done.
+	ShuffleSeed      *int64       // Set when tests were run in random order.
+
+	// Snapshots not matched by tests anymore, as in "__snapshots__/S.snap: TestX 1".
+	ObsoleteSnapshots []string
+}
+
+// TestResult describes how a single test went.
+type TestResult struct {
+	Suite     string
+	Method    string
+	Status    string // As labeled in the output, e.g. "PASS", "FAIL" or "SKIP".
+	StartTime time.Time
+	Duration  time.Duration
+	Log       string
+	Failures  []string         // Locations of failed checks, as in "foo_test.go:42".
+	Reason    string           // Why the test was skipped or expected to fail.
+	Benchmark *BenchmarkResult // Unless it's not a benchmark.
+}
+
+func newTestResult(c *C) TestResult {
+	label, _ := callLabel(c)
+	c.failuresM.Lock()
+	failures := append([]string(nil), c.failures...)
+	c.failuresM.Unlock()
+	var benchmark *BenchmarkResult
+	if c.status() != timedOutSt {
+		benchmark = c.benchmarkResult()
+	}
+	return TestResult{
+		Suite:     c.method.suiteName(),
+		Method:    c.method.Info.Name + c.subtestPath(),
+		Status:    label,
+		StartTime: c.startTime,
+		Duration:  c.elapsed(),
+		Log:       c.GetTestLog(),
+		Failures:  failures,
+		Reason:    c.reason,
+		Benchmark: benchmark,
+	}
+}
+
+type doneCall struct {
+	c    *C
+	test *TestResult
 }
 
 type resultTracker struct {
@@ -442,14 +991,14 @@ type resultTracker struct {
 	_waiting        int
 	_missed         int
 	_expectChan     chan *C
-	_doneChan       chan *C
+	_doneChan       chan doneCall
 	_stopChan       chan bool
 }
 
 func newResultTracker() *resultTracker {
 	return &resultTracker{_expectChan: make(chan *C), // Synchronous
-		_doneChan: make(chan *C, 32), // Asynchronous
-		_stopChan: make(chan bool)}   // Synchronous
+		_doneChan: make(chan doneCall, 32), // Asynchronous
+		_stopChan: make(chan bool)}         // Synchronous
 }
 
 func (tracker *resultTracker) start() {
@@ -464,8 +1013,14 @@ func (tracker *resultTracker) expectCall(c *C) {
 	tracker._expectChan <- c
 }
 
+// callDone must be called before the output consumes the log of c.
 func (tracker *resultTracker) callDone(c *C) {
-	tracker._doneChan <- c
+	done := doneCall{c: c}
+	if c.kind != fixtureKd {
+		test := newTestResult(c)
+		done.test = &test
+	}
+	tracker._doneChan <- done
 }
 
 func (tracker *resultTracker) _loopRoutine() {
@@ -477,13 +1032,24 @@ func (tracker *resultTracker) _loopRoutine() {
 			// XXX Reindent this (not now to make diff clear)
 			case <-tracker._expectChan:
 				tracker._waiting++
-			case c = <-tracker._doneChan:
+			case done := <-tracker._doneChan:
+				c = done.c
 				tracker._waiting--
+				if done.test != nil {
+					tracker.result.Tests = append(tracker.result.Tests, *done.test)
+				}
+				if c.retrying || c.kind == subtestKd {
+					// Counted once the last attempt is done, and
+					// subtests are counted by their test.
+					break
+				}
 				switch c.status() {
 				case succeededSt:
 					if c.kind == testKd {
 						if c.mustFail {
 							tracker.result.ExpectedFailures++
+						} else if c.attempt > 0 {
+							tracker.result.Flaky++
 						} else {
 							tracker.result.Succeeded++
 						}
@@ -502,6 +1068,8 @@ func (tracker *resultTracker) _loopRoutine() {
 					tracker.result.Missed++
 				case missedSt:
 					tracker.result.Missed++
+				case timedOutSt:
+					tracker.result.TimedOut++
 				case skippedSt:
 					if c.kind == testKd {
 						tracker.result.Skipped++
@@ -538,7 +1106,20 @@ type suiteRunner struct {
 	benchTime                 time.Duration
 	benchMem                  bool
 	testingT                  *testing.T
-	formatter                 formatters.Formatter
+	parallel                  *parallelGate
+	parallelTests             []*C
+	testTimeout               time.Duration
+	failedFast                *uint32
+	problems                  uint32 // Calls which failed, panicked or timed out.
+	subFilters                []*regexp.Regexp
+	suiteCleanups             *cleanupStack
+	count                     int
+	untilFail                 bool
+	retries                   int
+	detectLeaks               bool
+	ignoredLeaks              []string
+	suiteLeaks                *leakSnapshot
+	snapshots                 *snapshotFile
 	formatPrefix              string
 }
 
@@ -551,9 +1132,28 @@ type RunConf struct {
 	BenchmarkTime time.Duration // Defaults to 1 second
 	BenchmarkMem  bool
 	KeepWorkDir   bool
+	Parallel      int                  // Max number of parallel tests run at once, defaults to GOMAXPROCS
+	TestTimeout   time.Duration        // Abandon tests running for longer, unless zero
+	ReportOutput  io.Writer            // Where the formatter's end of run report (e.g. JUnit) goes, defaults to Output
+	Formatter     formatters.Formatter // Renders the output, defaults to the plain text one
+	Shuffle       string               // Run suites and tests in random order: "off", "on" or the seed to use
+	FailFast      bool                 // Don't start any other test after the first failure
+	Count         int                  // Run all tests this many times, defaults to 1
+	UntilFail     bool                 // Run all tests again and again, until some test fails, in rounds running all suites with RunAll
+	Retries       int                  // Run failed tests again up to this many times, reporting them FLAKY if they pass, except tests calling C.Parallel
+	DetectLeaks   bool                 // Fail tests and suites leaving goroutines behind
+	IgnoreLeaks   []string             // Prefixes of functions run by goroutines which aren't leaks, such as "net/http."
 	testingT      *testing.T
-	formatter     formatters.Formatter
 	formatPrefix  string
+	running       bool
+	failedFast    *uint32
+}
+
+// TimeoutSuite may be implemented by suites which need a test timeout
+// other than the one set by RunConf.TestTimeout.  A zero duration keeps
+// the configured timeout, and a negative one disables it for the suite.
+type TimeoutSuite interface {
+	Timeout() time.Duration
 }
 
 // Create a new suiteRunner able to run all methods in the given suite.
@@ -574,32 +1174,65 @@ func newSuiteRunner(suite any, runConf *RunConf) *suiteRunner {
 	suiteValue := reflect.ValueOf(suite)
 
 	runner := &suiteRunner{
-		suite:     suite,
-		output:    newOutputWriter(conf.Output, conf.Stream, conf.Verbose),
-		tracker:   newResultTracker(),
-		benchTime: conf.BenchmarkTime,
-		benchMem:  conf.BenchmarkMem,
-		tempDir:   &tempDir{},
-		keepDir:   conf.KeepWorkDir,
-		tests:     make([]*methodType, 0, suiteNumMethods),
-		testingT:  conf.testingT,
-
-		formatter:    conf.formatter,
+		suite:         suite,
+		output:        newOutputWriter(conf.Output, conf.Formatter, conf.Stream, conf.Verbose),
+		tracker:       newResultTracker(),
+		benchTime:     conf.BenchmarkTime,
+		benchMem:      conf.BenchmarkMem,
+		tempDir:       &tempDir{},
+		suiteCleanups: new(cleanupStack),
+		snapshots:     newSnapshotFile(suite),
+		keepDir:       conf.KeepWorkDir,
+		tests:         make([]*methodType, 0, suiteNumMethods),
+		testingT:      conf.testingT,
+		parallel:      newParallelGate(conf.Parallel),
+		testTimeout:   conf.TestTimeout,
+		failedFast:    conf.failedFast,
+		count:         conf.Count,
+		untilFail:     conf.UntilFail,
+		retries:       conf.Retries,
+		detectLeaks:   conf.DetectLeaks,
+		ignoredLeaks:  append(append([]string(nil), defaultIgnoredLeaks...), conf.IgnoreLeaks...),
+
 		formatPrefix: conf.formatPrefix,
 	}
 	if runner.benchTime == 0 {
 		runner.benchTime = 1 * time.Second
 	}
+	if runner.count <= 0 {
+		runner.count = 1
+	}
+	if s, ok := suite.(TimeoutSuite); ok && s.Timeout() != 0 {
+		runner.testTimeout = s.Timeout()
+	}
+	if s, ok := suite.(LeakSuite); ok {
+		runner.detectLeaks = s.DetectLeaks()
+		runner.ignoredLeaks = append(runner.ignoredLeaks, s.IgnoreLeaks()...)
+	}
 
 	var filterRegexp *regexp.Regexp
 	if conf.Filter != "" {
-		regexp, err := regexp.Compile(conf.Filter)
-		if err != nil {
-			msg := "Bad filter expression: " + err.Error()
-			runner.tracker.result.RunError = errors.New(msg)
-			return runner
+		// Every level of subtests has its own filter, as in "Test/sub".
+		filters := strings.Split(conf.Filter, "/")
+		for i, filter := range filters {
+			regexp, err := regexp.Compile(filter)
+			if err != nil {
+				msg := "Bad filter expression: " + err.Error()
+				runner.tracker.result.RunError = errors.New(msg)
+				return runner
+			}
+			if i == 0 {
+				filterRegexp = regexp
+			} else {
+				runner.subFilters = append(runner.subFilters, regexp)
+			}
 		}
-		filterRegexp = regexp
+	}
+
+	seed, shuffle, err := shuffleSeed(conf.Shuffle)
+	if err != nil {
+		runner.tracker.result.RunError = err
+		return runner
 	}
 
 	for i := 0; i != suiteNumMethods; i++ {
@@ -626,6 +1259,13 @@ func newSuiteRunner(suite any, runConf *RunConf) *suiteRunner {
 			}
 		}
 	}
+
+	if shuffle {
+		rand.New(rand.NewSource(seed)).Shuffle(len(runner.tests), func(i, j int) {
+			runner.tests[i], runner.tests[j] = runner.tests[j], runner.tests[i]
+		})
+		runner.tracker.result.ShuffleSeed = &seed
+	}
 	return runner
 }
 
@@ -633,13 +1273,15 @@ func newSuiteRunner(suite any, runConf *RunConf) *suiteRunner {
 func (runner *suiteRunner) run() *CheckTestResult {
 	if runner.tracker.result.RunError == nil && len(runner.tests) > 0 {
 		runner.tracker.start()
-		if runner.checkFixtureArgs() {
-			c := runner.runFixture(runner.setUpSuite, "", nil)
+		runner.output.WriteSuiteStarted(runner.tests[0].suiteName())
+		if runner.stopping() {
+			runner.skipTests(missedSt, runner.tests)
+		} else if runner.checkFixtureArgs() {
+			runner.suiteLeaks = runner.snapshotLeaks()
+			c := runner.runFixture(runner.setUpSuite, "", nil, runner.suiteCleanups)
 			if c == nil || c.status() == succeededSt {
-				for i := 0; i != len(runner.tests); i++ {
-					c := runner.runTest(runner.tests[i])
-					if c.status() == fixturePanickedSt {
-						runner.skipTests(missedSt, runner.tests[i+1:])
+				for round := 0; runner.untilFail || round < runner.count; round++ {
+					if !runner.runRound() {
 						break
 					}
 				}
@@ -648,11 +1290,22 @@ func (runner *suiteRunner) run() *CheckTestResult {
 			} else {
 				runner.skipTests(missedSt, runner.tests)
 			}
-			runner.runFixture(runner.tearDownSuite, "", nil)
+			runner.runFixture(runner.tearDownSuite, "", nil, runner.suiteCleanups)
+			if runner.tearDownSuite == nil && runner.setUpSuite != nil {
+				// Problems of the cleanups and leaks are reported on
+				// behalf of SetUpSuite, which was reported already.
+				// Suites without either fixture are left to the leak
+				// checks of their tests.
+				runner.runQuietFunc(runner.setUpSuite, func(c *C) {
+					defer c.checkLeaks(runner.suiteLeaks)
+					runner.suiteCleanups.run()
+				})
+			}
 		} else {
 			runner.skipTests(missedSt, runner.tests)
 		}
 		runner.tracker.waitAndStop()
+		runner.tracker.result.ObsoleteSnapshots = runner.snapshots.obsolete(runner)
 		if runner.keepDir {
 			runner.tracker.result.WorkDir = runner.tempDir.path
 		} else {
@@ -664,14 +1317,25 @@ func (runner *suiteRunner) run() *CheckTestResult {
 
 // Create a call object with the given suite method, and fork a
 // goroutine with the provided dispatcher for running it.
-func (runner *suiteRunner) forkCall(method *methodType, kind funcKind, testName string, logb *logger, dispatcher func(c *C)) *C {
-	var logw io.Writer
+func (runner *suiteRunner) forkCall(method *methodType, kind funcKind, testName string, logb *logger, cleanups *cleanupStack, dispatcher func(c *C)) *C {
+	c := runner.newCall(method, kind, testName, logb, cleanups)
+	runner.startCall(c, dispatcher)
+	return c
+}
+
+// Create a call object with the given suite method, expected by the
+// tracker but not started yet.
+func (runner *suiteRunner) newCall(method *methodType, kind funcKind, testName string, logb *logger, cleanups *cleanupStack) *C {
+	var logw *outputWriter
 	if runner.output.Stream {
 		logw = runner.output
 	}
 	if logb == nil {
 		logb = new(logger)
 	}
+	if cleanups == nil {
+		cleanups = new(cleanupStack)
+	}
 	c := &C{
 		method:    method,
 		kind:      kind,
@@ -685,21 +1349,42 @@ func (runner *suiteRunner) forkCall(method *methodType, kind funcKind, testName
 		benchMem:  runner.benchMem,
 		testingT:  runner.testingT,
 
-		formatter:    runner.formatter,
+		parallel:   runner.parallel,
+		parallelCh: make(chan bool, 1),
+
+		runner:       runner,
+		cleanups:     cleanups,
 		formatPrefix: runner.formatPrefix,
 	}
 	runner.tracker.expectCall(c)
+	return c
+}
+
+// Fork a goroutine running the call with the provided dispatcher.
+func (runner *suiteRunner) startCall(c *C, dispatcher func(c *C)) {
 	go (func() {
-		runner.reportCallStarted(c)
+		if !c.quiet {
+			runner.reportCallStarted(c)
+		}
 		defer runner.callDone(c)
 		dispatcher(c)
 	})()
-	return c
 }
 
 // Same as forkCall(), but wait for call to finish before returning.
 func (runner *suiteRunner) runFunc(method *methodType, kind funcKind, testName string, logb *logger, dispatcher func(c *C)) *C {
-	c := runner.forkCall(method, kind, testName, logb, dispatcher)
+	c := runner.forkCall(method, kind, testName, logb, nil, dispatcher)
+	<-c.done
+	return c
+}
+
+// Same as runFunc(), but for a fixture call which is only reported if it
+// has a problem, since the fixture was reported once already.
+func (runner *suiteRunner) runQuietFunc(method *methodType, dispatcher func(c *C)) *C {
+	c := runner.newCall(method, fixtureKd, "", nil, nil)
+	c.quiet = true
+	c.logw = nil // Written along with the problem, if any.
+	runner.startCall(c, dispatcher)
 	<-c.done
 	return c
 }
@@ -708,6 +1393,14 @@ func (runner *suiteRunner) runFunc(method *methodType, kind funcKind, testName s
 // accordingly.  Then, mark the call as done and report to the tracker.
 func (runner *suiteRunner) callDone(c *C) {
 	value := recover()
+	if !c.finish() {
+		// Timed out and reported already.
+		return
+	}
+	if c.watchdog != nil {
+		c.watchdog.Stop()
+	}
+	c.releaseSlot()
 	if value != nil {
 		switch v := value.(type) {
 		case *fixturePanic:
@@ -741,28 +1434,45 @@ func (runner *suiteRunner) callDone(c *C) {
 // goroutine like all suite methods, but this method will not return
 // while the fixture goroutine is not done, because the fixture must be
 // run in a desired order.
-func (runner *suiteRunner) runFixture(method *methodType, testName string, logb *logger) *C {
+func (runner *suiteRunner) runFixture(method *methodType, testName string, logb *logger, cleanups *cleanupStack) *C {
 	if method != nil {
-		c := runner.runFunc(method, fixtureKd, testName, logb, func(c *C) {
-			c.ResetTimer()
-			c.StartTimer()
-			defer c.StopTimer()
-			c.method.Call([]reflect.Value{reflect.ValueOf(c)})
-		})
+		c := runner.forkFixture(method, testName, logb, cleanups)
+		<-c.done
 		return c
 	}
 	return nil
 }
 
-// Run the fixture method with runFixture(), but panic with a fixturePanic{}
-// in case the fixture method panics.  This makes it easier to track the
-// fixture panic together with other call panics within forkTest().
-func (runner *suiteRunner) runFixtureWithPanic(method *methodType, testName string, logb *logger, skipped *bool) *C {
-	if skipped != nil && *skipped {
+// Functions registered with Cleanup go into the given stack, which is run
+// once the fixture is over, unless it's a set up one.  The stacks given to
+// set up fixtures are run by the matching tear down ones.  TearDownSuite
+// then reports the goroutines leaked by the suite, if leaks are detected.
+func (runner *suiteRunner) forkFixture(method *methodType, testName string, logb *logger, cleanups *cleanupStack) *C {
+	return runner.forkCall(method, fixtureKd, testName, logb, cleanups, func(c *C) {
+		if method == runner.tearDownSuite {
+			defer c.checkLeaks(runner.suiteLeaks)
+		}
+		if method != runner.setUpSuite && method != runner.setUpTest {
+			defer c.cleanups.run()
+		}
+		c.ResetTimer()
+		c.StartTimer()
+		defer c.StopTimer()
+		c.method.Call([]reflect.Value{reflect.ValueOf(c)})
+	})
+}
+
+// Run the fixture method on behalf of the given test, but panic with a
+// fixturePanic{} in case the fixture method panics.  This makes it easier
+// to track the fixture panic together with other call panics within
+// forkTest().
+func (runner *suiteRunner) runFixtureWithPanic(test *C, method *methodType, logb *logger, skipped *bool) *C {
+	if method == nil || skipped != nil && *skipped {
 		return nil
 	}
-	c := runner.runFixture(method, testName, logb)
-	if c != nil && c.status() != succeededSt {
+	c := runner.forkTestFixture(test, method, logb)
+	<-c.done
+	if c.status() != succeededSt {
 		if skipped != nil {
 			*skipped = c.status() == skippedSt
 		}
@@ -771,6 +1481,20 @@ func (runner *suiteRunner) runFixtureWithPanic(method *methodType, testName stri
 	return c
 }
 
+// Fork the fixture method on behalf of the given test, and record it, so
+// that the fixture is abandoned along with the test if the test times out
+// while waiting for it.
+func (runner *suiteRunner) forkTestFixture(test *C, method *methodType, logb *logger) *C {
+	test.fixtureM.Lock()
+	defer test.fixtureM.Unlock()
+	if atomic.LoadUint32(&test._finished) != 0 {
+		// Timed out already, so nothing else is run for the test.
+		runtime.Goexit()
+	}
+	test.fixture = runner.forkFixture(method, test.testName, logb, test.fixtureCleanups)
+	return test.fixture
+}
+
 type fixturePanic struct {
 	status funcStatus
 	method *methodType
@@ -778,15 +1502,31 @@ type fixturePanic struct {
 
 // Run the suite test method, together with the test-specific fixture,
 // asynchronously.
-func (runner *suiteRunner) forkTest(method *methodType) *C {
+func (runner *suiteRunner) forkTest(method *methodType, attempt int) *C {
 	testName := method.String()
-	return runner.forkCall(method, testKd, testName, nil, func(c *C) {
+	return runner.forkCall(method, testKd, testName, nil, nil, func(c *C) {
+		c.attempt = attempt
+		c.fixtureCleanups = new(cleanupStack)
+		leaks := runner.snapshotLeaks()
 		var skipped bool
-		defer runner.runFixtureWithPanic(runner.tearDownTest, testName, nil, &skipped)
+		defer func() {
+			if c.startTearDown() {
+				defer func() {
+					// Goroutines of parallel tests can't be told apart.
+					if !c.isParallel {
+						c.checkLeaks(leaks)
+					}
+				}()
+				defer c.fixtureCleanups.run()
+				runner.runFixtureWithPanic(c, runner.tearDownTest, nil, &skipped)
+			}
+		}()
+		defer c.cleanups.run()
 		defer c.StopTimer()
+		runner.startWatchdog(c)
 		benchN := 1
 		for {
-			runner.runFixtureWithPanic(runner.setUpTest, testName, c.logb, &skipped)
+			runner.runFixtureWithPanic(c, runner.setUpTest, c.logb, &skipped)
 			mt := c.method.Type()
 			if mt.NumIn() != 1 || mt.In(0) != reflect.TypeOf(c) {
 				// Rather than a plain panic, provide a more helpful message when
@@ -827,17 +1567,130 @@ func (runner *suiteRunner) forkTest(method *methodType) *C {
 			benchN = roundUp(benchN)
 
 			skipped = true // Don't run the deferred one if this panics.
-			runner.runFixtureWithPanic(runner.tearDownTest, testName, nil, nil)
+			runner.runFixtureWithPanic(c, runner.tearDownTest, nil, nil)
 			skipped = false
 		}
 	})
 }
 
+// Arm the test timeout, if any, for the given test call.
+func (runner *suiteRunner) startWatchdog(c *C) {
+	if runner.testTimeout > 0 {
+		c.deadline = time.Now().Add(runner.testTimeout)
+		c.watchdog = time.AfterFunc(runner.testTimeout, func() {
+			runner.timeoutCall(c)
+		})
+	}
+}
+
+// Abandon a call which has been running for longer than the test timeout.
+// The call is reported as timed out along with a dump of all goroutines,
+// and TearDownTest is run on its behalf if it didn't start yet.  A test
+// stuck in SetUpTest or TearDownTest is reported as missed instead, and
+// the fixture is the one timing out.  The goroutine of the call itself is
+// left behind, since it can't be stopped.
+func (runner *suiteRunner) timeoutCall(c *C) {
+	if !c.finish() {
+		// Finished just in time.
+		return
+	}
+	c.releaseSlot()
+	fc := c.pendingFixture()
+	if fc != nil && fc.finish() {
+		runner.expireCall(fc)
+		runner.reportCallDone(fc)
+		c.setStatus(missedSt)
+		c.logString("... Fixture has timed out (see related TIMEOUT)")
+	} else {
+		fc = nil
+		runner.expireCall(c)
+	}
+	if c.kind == testKd && runner.tearDownTest != nil && c.startTearDown() {
+		tc := runner.forkFixture(runner.tearDownTest, c.testName, nil, c.fixtureCleanups)
+		select {
+		case <-tc.done:
+		case <-time.After(runner.testTimeout):
+			runner.timeoutCall(tc)
+			<-tc.done
+		}
+	}
+	runner.reportCallDone(c)
+	// Only wake up the calls once they're reported, since they may then
+	// carry on.
+	if fc != nil {
+		fc.cleanups.cancel(context.DeadlineExceeded)
+		fc.done <- fc
+	}
+	c.cleanups.cancel(context.DeadlineExceeded)
+	c.done <- c
+}
+
+// Mark the finished call as timed out, logging why.
+func (runner *suiteRunner) expireCall(c *C) {
+	c.setStatus(timedOutSt)
+	c.logf("... Timeout: still running after %s", runner.testTimeout)
+	c.logGoroutines()
+}
+
+// pendingFixture returns the last fixture call run on behalf of the test,
+// which it may be still waiting for.  It must be called once the test is
+// finished, so no other fixture starts afterwards.
+func (c *C) pendingFixture() *C {
+	c.fixtureM.Lock()
+	defer c.fixtureM.Unlock()
+	return c.fixture
+}
+
 // Same as forkTest(), but wait for the test to finish before returning.
+// Tests calling C.Parallel are only waited for until they pause, and are
+// collected to be waited for by waitParallelTests().
 func (runner *suiteRunner) runTest(method *methodType) *C {
-	c := runner.forkTest(method)
-	<-c.done
-	return c
+	for attempt := 0; ; attempt++ {
+		c := runner.forkTest(method, attempt)
+		select {
+		case <-c.done:
+		case <-c.parallelCh:
+			runner.parallelTests = append(runner.parallelTests, c)
+			return c
+		}
+		if !c.retrying {
+			return c
+		}
+	}
+}
+
+// Resume the paused parallel tests and wait for all of them to finish.
+func (runner *suiteRunner) waitParallelTests() {
+	close(runner.parallel.release)
+	for _, c := range runner.parallelTests {
+		<-c.done
+	}
+	runner.parallelTests = nil
+	runner.parallel = &parallelGate{slots: runner.parallel.slots, release: make(chan bool)}
+}
+
+// runRound runs all tests once, returning whether another round may follow.
+func (runner *suiteRunner) runRound() bool {
+	problems := atomic.LoadUint32(&runner.problems)
+	more := true
+	for i := 0; i != len(runner.tests); i++ {
+		if runner.stopping() {
+			runner.skipTests(missedSt, runner.tests[i:])
+			more = false
+			break
+		}
+		c := runner.runTest(runner.tests[i])
+		if c.status() == fixturePanickedSt {
+			runner.skipTests(missedSt, runner.tests[i+1:])
+			more = false
+			break
+		}
+	}
+	runner.waitParallelTests()
+	if runner.untilFail && atomic.LoadUint32(&runner.problems) != problems {
+		return false
+	}
+	return more
 }
 
 // Helper to mark tests as skipped or missed.  A bit heavy for what
@@ -845,7 +1698,7 @@ func (runner *suiteRunner) runTest(method *methodType) *C {
 // nice verbose output.
 func (runner *suiteRunner) skipTests(status funcStatus, methods []*methodType) {
 	for _, method := range methods {
-		runner.runFunc(method, testKd, "", nil, func(c *C) {
+		runner.runFunc(method, testKd, method.String(), nil, func(c *C) {
 			c.setStatus(status)
 		})
 	}
@@ -876,27 +1729,70 @@ func (runner *suiteRunner) reportCallStarted(c *C) {
 }
 
 func (runner *suiteRunner) reportCallDone(c *C) {
+	if c.kind == testKd && !c.isParallel && c.attempt < runner.retries {
+		switch c.status() {
+		case failedSt, panickedSt, timedOutSt:
+			c.retrying = true
+		}
+	}
 	runner.tracker.callDone(c)
+	label, problem := callLabel(c)
+	if problem && !c.retrying {
+		atomic.AddUint32(&runner.problems, 1)
+		if runner.failedFast != nil {
+			atomic.StoreUint32(runner.failedFast, 1)
+		}
+	}
+	if problem {
+		if c.quiet {
+			runner.reportCallStarted(c)
+			if runner.output.Stream {
+				runner.output.WriteCallOutput(c, []byte(c.GetTestLog()))
+			}
+		}
+		runner.output.WriteCallProblem(label, c)
+	} else if !c.quiet {
+		runner.output.WriteCallSuccess(label, c)
+	}
+}
+
+// stopping returns whether no other test should be started, since some
+// call has failed in fail-fast mode.
+func (runner *suiteRunner) stopping() bool {
+	return runner.failedFast != nil && atomic.LoadUint32(runner.failedFast) != 0
+}
+
+// callLabel returns the label reporting the status of a finished call,
+// and whether it's a problem which deserves showing the call log.
+func callLabel(c *C) (label string, problem bool) {
+	if c.retrying {
+		return "RETRY", true
+	}
 	switch c.status() {
 	case succeededSt:
 		if c.mustFail {
-			runner.output.WriteCallSuccess("FAIL EXPECTED", c)
-		} else {
-			runner.output.WriteCallSuccess("PASS", c)
+			return "FAIL EXPECTED", false
+		}
+		if c.attempt > 0 {
+			return "FLAKY", false
 		}
+		return "PASS", false
 	case skippedSt:
-		runner.output.WriteCallSuccess("SKIP", c)
+		return "SKIP", false
 	case failedSt:
-		runner.output.WriteCallProblem("FAIL", c)
+		return "FAIL", true
 	case panickedSt:
-		runner.output.WriteCallProblem("PANIC", c)
+		return "PANIC", true
 	case fixturePanickedSt:
 		// That's a testKd call reporting that its fixture
 		// has panicked. The fixture call which caused the
 		// panic itself was tracked above. We'll report to
 		// aid debugging.
-		runner.output.WriteCallProblem("PANIC", c)
+		return "PANIC", true
 	case missedSt:
-		runner.output.WriteCallSuccess("MISS", c)
+		return "MISS", false
+	case timedOutSt:
+		return "TIMEOUT", true
 	}
+	return "", false
 }
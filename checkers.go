@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 
 	cf "github.com/iostrovok/go-convert"
@@ -743,6 +744,8 @@ type moreThan struct {
 //	  float32 => float32
 //    []byte, string => string
 //    float64 => float64
+//    time.Time => compared as instants
+//    time.Duration => time.Duration
 //
 
 var MoreThan Checker = &moreThan{
@@ -759,6 +762,16 @@ func (checker *moreThan) Check(params []any, _ []string) (result bool, error str
 		}
 	}()
 
+	if a, b, ok := timeParams(params); ok {
+		result = a.After(b)
+		params = []any{formatTime(a), formatTime(b)} // For the error message.
+		return
+	}
+	if a, b, ok := durationParams(params); ok {
+		result = a > b
+		return
+	}
+
 	if a := []bool{isStringType(params[0]), isStringType(params[1])}; a[0] || a[1] {
 		if a[0] && a[1] {
 			if result = cf.String(params[0]) > cf.String(params[1]); !result {
@@ -833,6 +846,8 @@ type lessThan struct {
 //	  float32 => float32
 //    []byte, string => string
 //    float64 => float64
+//    time.Time => compared as instants
+//    time.Duration => time.Duration
 //
 
 var LessThan Checker = &lessThan{
@@ -849,6 +864,16 @@ func (checker *lessThan) Check(params []any, _ []string) (result bool, error str
 		}
 	}()
 
+	if a, b, ok := timeParams(params); ok {
+		result = a.Before(b)
+		params = []any{formatTime(a), formatTime(b)} // For the error message.
+		return
+	}
+	if a, b, ok := durationParams(params); ok {
+		result = a < b
+		return
+	}
+
 	if a := []bool{isStringType(params[0]), isStringType(params[1])}; a[0] || a[1] {
 		if a[0] && a[1] {
 			result = cf.String(params[0]) < cf.String(params[1])
@@ -924,6 +949,8 @@ type moreOrEqualThan struct {
 //	  float32 => float32
 //    []byte, string => string
 //    float64 => float64
+//    time.Time => compared as instants
+//    time.Duration => time.Duration
 //
 
 var MoreOrEqualThan Checker = &moreOrEqualThan{
@@ -940,6 +967,16 @@ func (checker *moreOrEqualThan) Check(params []any, _ []string) (result bool, er
 		}
 	}()
 
+	if a, b, ok := timeParams(params); ok {
+		result = !a.Before(b)
+		params = []any{formatTime(a), formatTime(b)} // For the error message.
+		return
+	}
+	if a, b, ok := durationParams(params); ok {
+		result = a >= b
+		return
+	}
+
 	if a := []bool{isStringType(params[0]), isStringType(params[1])}; a[0] || a[1] {
 		if a[0] && a[1] {
 			if result = cf.String(params[0]) >= cf.String(params[1]); !result {
@@ -1011,6 +1048,8 @@ type lessOrEqualThan struct {
 //		  float32 => float32
 //	   []byte, string => string
 //	   float64 => float64
+//	   time.Time => compared as instants
+//	   time.Duration => time.Duration
 var LessOrEqualThan Checker = &lessOrEqualThan{
 	&CheckerInfo{Name: "MoreOrEqualThan", Params: []string{"get", "should be more or equal than"}},
 }
@@ -1025,6 +1064,16 @@ func (checker *lessOrEqualThan) Check(params []any, _ []string) (result bool, er
 		}
 	}()
 
+	if a, b, ok := timeParams(params); ok {
+		result = !a.After(b)
+		params = []any{formatTime(a), formatTime(b)} // For the error message.
+		return
+	}
+	if a, b, ok := durationParams(params); ok {
+		result = a <= b
+		return
+	}
+
 	if a := []bool{isStringType(params[0]), isStringType(params[1])}; a[0] || a[1] {
 		if a[0] && a[1] {
 			if result = cf.String(params[0]) <= cf.String(params[1]); !result {
@@ -1293,6 +1342,272 @@ func (checker *notContains) Check(params []any, _ []string) (result bool, error
 	return false, "expected value contains obtained value"
 }
 
+// -----------------------------------------------------------------------
+// ElementsMatch checker.
+
+type elementsMatch struct {
+	*CheckerInfo
+}
+
+// The ElementsMatch checker verifies that the obtained slice or array has
+// the same elements as the expected one, in any order.  Elements are
+// compared with ObjectsAreEqual, and duplicates must appear just as many
+// times on both sides.  On failure, the elements missing from the obtained
+// value and the extra ones are listed.
+//
+// For example:
+//
+//	c.Assert(ids, ElementsMatch, []int{3, 1, 2})
+var ElementsMatch Checker = &elementsMatch{
+	&CheckerInfo{Name: "ElementsMatch", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *elementsMatch) Check(params []any, _ []string) (bool, string) {
+	obtained, expected := reflect.ValueOf(params[0]), reflect.ValueOf(params[1])
+	if !isList(obtained) {
+		return false, "obtained value is not a slice or array"
+	}
+	if !isList(expected) {
+		return false, "expected value is not a slice or array"
+	}
+
+	// Distinct elements, in order of appearance, with how many more times
+	// they're expected than obtained.
+	var elements []any
+	var counts []int
+	count := func(v reflect.Value, n int) {
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i).Interface()
+			j := 0
+			for j < len(elements) && !ObjectsAreEqual(elements[j], e) {
+				j++
+			}
+			if j == len(elements) {
+				elements = append(elements, e)
+				counts = append(counts, 0)
+			}
+			counts[j] += n
+		}
+	}
+	count(expected, 1)
+	count(obtained, -1)
+
+	var lines []string
+	for i, e := range elements {
+		if counts[i] > 0 {
+			lines = append(lines, "missing: "+countedElement(e, counts[i]))
+		}
+	}
+	for i, e := range elements {
+		if counts[i] < 0 {
+			lines = append(lines, "extra: "+countedElement(e, -counts[i]))
+		}
+	}
+	if len(lines) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf(`Elements differ:
+%s`, formatMultiLine(strings.Join(lines, "\n"), false))
+}
+
+func isList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+}
+
+func countedElement(e any, n int) string {
+	if n == 1 {
+		return fmt.Sprintf("%#v", e)
+	}
+	return fmt.Sprintf("%#v (%d times)", e, n)
+}
+
+// -----------------------------------------------------------------------
+// IsSubsetOf and IsSupersetOf checkers.
+
+type subsetChecker struct {
+	*CheckerInfo
+	superset bool
+}
+
+// The IsSubsetOf checker verifies that all the elements of the obtained
+// slice or array are also in the expected one, or that all the keys of
+// the obtained map are also in the expected map, with the same values.
+// Elements and values are compared with ObjectsAreEqual.  On failure, the
+// unexpected elements or keys, and the keys with different values, are
+// listed.
+//
+// For example:
+//
+//	c.Assert([]string{"a", "c"}, IsSubsetOf, []string{"a", "b", "c"})
+//	c.Assert(headers, IsSubsetOf, map[string]string{"Accept": "*/*", "Host": "x"})
+var IsSubsetOf Checker = &subsetChecker{
+	&CheckerInfo{Name: "IsSubsetOf", Params: []string{"obtained", "expected"}},
+	false,
+}
+
+// The IsSupersetOf checker verifies that the obtained slice, array or map
+// has all the elements or keys of the expected one, just like IsSubsetOf
+// does the other way round.
+//
+// For example:
+//
+//	c.Assert(labels, IsSupersetOf, map[string]string{"app": "web"})
+var IsSupersetOf Checker = &subsetChecker{
+	&CheckerInfo{Name: "IsSupersetOf", Params: []string{"obtained", "expected"}},
+	true,
+}
+
+func (checker *subsetChecker) Check(params []any, _ []string) (bool, string) {
+	obtained, expected := reflect.ValueOf(params[0]), reflect.ValueOf(params[1])
+	switch {
+	case isList(obtained) && isList(expected):
+	case obtained.Kind() == reflect.Map && expected.Kind() == reflect.Map:
+	default:
+		return false, "obtained and expected values must be both slices or arrays, or both maps"
+	}
+
+	var lines []string
+	if checker.superset {
+		lines = subsetDiff(expected, obtained, "missing", false)
+	} else {
+		lines = subsetDiff(obtained, expected, "unexpected", true)
+	}
+	if len(lines) == 0 {
+		return true, ""
+	}
+	header := "Not a subset:"
+	if checker.superset {
+		header = "Not a superset:"
+	}
+	return false, fmt.Sprintf(`%s
+%s`, header, formatMultiLine(strings.Join(lines, "\n"), false))
+}
+
+// subsetDiff describes what makes sub not a subset of super, as lines such
+// as `missing: "a"`, `missing key "a": 1` or `key "a": 1 != 2`, where the
+// obtained value comes first.
+func subsetDiff(sub, super reflect.Value, absent string, subObtained bool) []string {
+	var lines []string
+	if isList(sub) {
+		for i := 0; i < sub.Len(); i++ {
+			e := sub.Index(i).Interface()
+			if _, found := containsElement(super.Interface(), e); !found {
+				lines = append(lines, fmt.Sprintf("%s: %#v", absent, e))
+			}
+		}
+		return lines
+	}
+	for _, key := range sortedMapKeys(sub) {
+		value := sub.MapIndex(key).Interface()
+		superKey, found := findMapKey(super, key.Interface())
+		if !found {
+			lines = append(lines, fmt.Sprintf("%s key %#v: %#v", absent, key.Interface(), value))
+			continue
+		}
+		superValue := super.MapIndex(superKey).Interface()
+		if !ObjectsAreEqual(value, superValue) {
+			obtained, expected := value, superValue
+			if !subObtained {
+				obtained, expected = superValue, value
+			}
+			lines = append(lines, fmt.Sprintf("key %#v: %#v != %#v", key.Interface(), obtained, expected))
+		}
+	}
+	return lines
+}
+
+// sortedMapKeys returns the keys of the map m, sorted by their Go syntax
+// representation so that messages are stable.
+func sortedMapKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprintf("%#v", keys[i].Interface()) < fmt.Sprintf("%#v", keys[j].Interface())
+	})
+	return keys
+}
+
+// findMapKey returns the key of the map m which equals key.
+func findMapKey(m reflect.Value, key any) (reflect.Value, bool) {
+	for _, k := range m.MapKeys() {
+		if ObjectsAreEqual(k.Interface(), key) {
+			return k, true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// -----------------------------------------------------------------------
+// HasKeys and HasValues checkers.
+
+type hasItemsChecker struct {
+	*CheckerInfo
+	values bool
+}
+
+// The HasKeys checker verifies that the obtained map has all the keys in
+// the expected slice or array, which are compared with ObjectsAreEqual.
+// On failure, the missing keys are listed.
+//
+// For example:
+//
+//	c.Assert(config, HasKeys, []string{"name", "port"})
+var HasKeys Checker = &hasItemsChecker{
+	&CheckerInfo{Name: "HasKeys", Params: []string{"obtained", "expected"}},
+	false,
+}
+
+// The HasValues checker verifies that the obtained map has all the values
+// in the expected slice or array, under any keys, just like HasKeys does
+// for keys.
+//
+// For example:
+//
+//	c.Assert(ports, HasValues, []int{80, 443})
+var HasValues Checker = &hasItemsChecker{
+	&CheckerInfo{Name: "HasValues", Params: []string{"obtained", "expected"}},
+	true,
+}
+
+func (checker *hasItemsChecker) Check(params []any, _ []string) (bool, string) {
+	obtained, expected := reflect.ValueOf(params[0]), reflect.ValueOf(params[1])
+	if obtained.Kind() != reflect.Map {
+		return false, "obtained value is not a map"
+	}
+	if !isList(expected) {
+		return false, "expected value is not a slice or array"
+	}
+
+	items := obtained.MapKeys()
+	if checker.values {
+		for i, key := range items {
+			items[i] = obtained.MapIndex(key)
+		}
+	}
+	var missing []string
+	for i := 0; i < expected.Len(); i++ {
+		e := expected.Index(i).Interface()
+		found := false
+		for _, item := range items {
+			if ObjectsAreEqual(item.Interface(), e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, fmt.Sprintf("%#v", e))
+		}
+	}
+	if len(missing) == 0 {
+		return true, ""
+	}
+	what := "keys"
+	if checker.values {
+		what = "values"
+	}
+	return false, fmt.Sprintf(`Missing %s:
+%s`, what, formatMultiLine(strings.Join(missing, "\n"), false))
+}
+
 // -----------------------------------------------------------------------
 // errorIs checker.
 
@@ -0,0 +1,194 @@
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// -----------------------------------------------------------------------
+// Bind, AllOf, AnyOf, Satisfies and WithMessage checker combinators.
+
+// Bind returns a checker which runs the provided checker with the given
+// expected arguments, so it only takes the obtained value.  It's meant to
+// build checkers from others, mostly with AllOf and AnyOf.
+//
+// For example:
+//
+//	c.Assert(n, Bind(MoreThan, 0))
+//	c.Assert(name, AllOf(Bind(HasLen, 3), Bind(Matches, "[a-z]+")))
+func Bind(checker Checker, args ...any) Checker {
+	info := checker.Info()
+	if len(args) != len(info.Params)-1 {
+		panic(fmt.Sprintf("Bind: %s takes %d expected arguments, got %d", info.Name, len(info.Params)-1, len(args)))
+	}
+	values := make([]string, len(args))
+	for i, arg := range args {
+		values[i] = fmt.Sprintf("%#v", arg)
+	}
+	return &boundChecker{
+		&CheckerInfo{Name: info.Name + "(" + strings.Join(values, ", ") + ")", Params: info.Params[:1]},
+		checker,
+		args,
+	}
+}
+
+type boundChecker struct {
+	*CheckerInfo
+	sub  Checker
+	args []any
+}
+
+func (checker *boundChecker) Check(params []any, names []string) (bool, string) {
+	subNames := append([]string{}, checker.sub.Info().Params...)
+	result, error := checker.sub.Check(append([]any{params[0]}, checker.args...), subNames)
+	names[0] = subNames[0]
+	return result, error
+}
+
+// The AllOf checker verifies that the obtained value passes all of the
+// provided checkers, which take only the obtained value: use Bind for
+// checkers which take expected arguments.  On failure, each of the
+// checkers which failed is listed along with its error.
+//
+// For example:
+//
+//	c.Assert(list, AllOf(NotNil, Bind(HasLen, 3)))
+func AllOf(checkers ...Checker) Checker {
+	return newCombinedChecker("AllOf", checkers, true)
+}
+
+// The AnyOf checker verifies that the obtained value passes at least one
+// of the provided checkers, just like AllOf does for all of them.  On
+// failure, all the checkers are listed along with their errors.
+//
+// For example:
+//
+//	c.Assert(err, AnyOf(IsNil, Bind(ErrorIs, fs.ErrNotExist)))
+func AnyOf(checkers ...Checker) Checker {
+	return newCombinedChecker("AnyOf", checkers, false)
+}
+
+type combinedChecker struct {
+	*CheckerInfo
+	checkers []Checker
+	all      bool
+}
+
+func newCombinedChecker(name string, checkers []Checker, all bool) Checker {
+	if len(checkers) == 0 {
+		panic(name + " needs at least one checker")
+	}
+	for _, checker := range checkers {
+		if info := checker.Info(); len(info.Params) != 1 {
+			panic(fmt.Sprintf("%s: %s takes expected arguments, use Bind(%s, ...)", name, info.Name, info.Name))
+		}
+	}
+	return &combinedChecker{&CheckerInfo{Name: name, Params: []string{"obtained"}}, checkers, all}
+}
+
+func (checker *combinedChecker) Check(params []any, _ []string) (bool, string) {
+	var failures []string
+	for _, sub := range checker.checkers {
+		info := sub.Info()
+		result, error := sub.Check(params, append([]string{}, info.Params...))
+		if result && error == "" {
+			if !checker.all {
+				return true, ""
+			}
+			continue
+		}
+		if error == "" {
+			failures = append(failures, info.Name+" failed")
+		} else {
+			failures = append(failures, info.Name+" failed: "+error)
+		}
+	}
+	if len(failures) == 0 {
+		return true, ""
+	}
+	header := "Failed checks:"
+	if !checker.all {
+		header = "No check passed:"
+	}
+	return false, fmt.Sprintf(`%s
+%s`, header, formatMultiLine(strings.Join(failures, "\n"), false))
+}
+
+// Satisfies returns a checker which verifies that the obtained value
+// satisfies the predicate, as described.  The obtained value must be of
+// the type the predicate takes, or nil for pointers and interfaces.
+//
+// For example:
+//
+//	isEven := Satisfies(func(n int) bool { return n%2 == 0 }, "is even")
+//	c.Assert(n, isEven)
+func Satisfies[T any](predicate func(T) bool, description string) Checker {
+	return &satisfiesChecker[T]{
+		&CheckerInfo{Name: "Satisfies", Params: []string{"obtained"}},
+		predicate,
+		description,
+	}
+}
+
+type satisfiesChecker[T any] struct {
+	*CheckerInfo
+	predicate   func(T) bool
+	description string
+}
+
+func (checker *satisfiesChecker[T]) Check(params []any, _ []string) (bool, string) {
+	var value T
+	if params[0] != nil {
+		var ok bool
+		if value, ok = params[0].(T); !ok {
+			return false, fmt.Sprintf("obtained value is not a %s", reflect.TypeOf(&value).Elem())
+		}
+	} else if t := reflect.TypeOf(&value).Elem(); !isNillable(t.Kind()) {
+		return false, fmt.Sprintf("obtained value is not a %s", t)
+	}
+	if checker.predicate(value) {
+		return true, ""
+	}
+	return false, "Predicate not satisfied: " + checker.description
+}
+
+func isNillable(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice:
+		return true
+	}
+	return false
+}
+
+// WithMessage returns a checker which behaves just like the provided one,
+// but reports the given message on failure, followed by the error of the
+// checker, if any.
+//
+// For example:
+//
+//	c.Assert(resp.StatusCode, WithMessage(Equals, "the user must be found"), 200)
+func WithMessage(checker Checker, message string) Checker {
+	return &messageChecker{checker, message}
+}
+
+type messageChecker struct {
+	sub     Checker
+	message string
+}
+
+func (checker *messageChecker) Info() *CheckerInfo {
+	return checker.sub.Info()
+}
+
+func (checker *messageChecker) Check(params []any, names []string) (bool, string) {
+	result, error := checker.sub.Check(params, names)
+	if result && error == "" {
+		return result, error
+	}
+	if error == "" {
+		return false, checker.message
+	}
+	return false, fmt.Sprintf(`%s:
+%s`, checker.message, formatMultiLine(error, false))
+}
@@ -0,0 +1,96 @@
+package check_test
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/iostrovok/check"
+)
+
+func (s *CheckersS) TestBind(c *check.C) {
+	testInfo(c, check.Bind(check.Equals, 42), "Equals(42)", []string{"obtained"})
+	testInfo(c, check.Bind(check.Matches, "a.*"), `Matches("a.*")`, []string{"value"})
+	testInfo(c, check.Not(check.Bind(check.Equals, 1)), "Not(Equals(1))", []string{"obtained"})
+
+	testCheck(c, check.Bind(check.Equals, 42), true, "", 42)
+	testCheck(c, check.Bind(check.Equals, 42), false, "", 41)
+	testCheck(c, check.Bind(check.InDelta, 1.0, 0.1), false, "Difference: 2 != 1, delta 1 > 0.1", 2.0)
+	testCheck(c, check.Not(check.Bind(check.Equals, 1)), true, "", 2)
+
+	_, names := testCheck(c, check.Bind(check.ErrorMatches, "x"), false, "", errors.New("y"))
+	c.Assert(names, check.DeepEquals, []string{"error"})
+
+	c.Assert(func() { check.Bind(check.Equals) }, check.PanicMatches, "Bind: Equals takes 1 expected arguments, got 0")
+}
+
+func (s *CheckersS) TestAllOf(c *check.C) {
+	testInfo(c, check.AllOf(check.NotNil), "AllOf", []string{"obtained"})
+
+	testCheck(c, check.AllOf(check.NotNil, check.Bind(check.HasLen, 2)), true, "", []int{1, 2})
+	testCheck(c, check.AllOf(check.NotNil, check.Bind(check.HasLen, 3), check.Bind(check.Contains, []int{5})), false,
+		"Failed checks:\n"+
+			"...     HasLen(3) failed\n"+
+			"...     Contains([]int{5}) failed: expected does not contain obtained\n",
+		[]int{1, 2})
+	testCheck(c, check.AllOf(check.AnyOf(check.IsNil, check.Bind(check.JSONEquals, `{"a": 1}`))), false,
+		"Failed checks:\n"+
+			"...     AnyOf failed: No check passed:\n"+
+			"...     ...     IsNil failed\n"+
+			"...     ...     JSONEquals(\"{\\\"a\\\": 1}\") failed: JSON difference:\n"+
+			"...     ...     ...     $.a: 2 != 1\n",
+		`{"a": 2}`)
+
+	c.Assert(func() { check.AllOf() }, check.PanicMatches, "AllOf needs at least one checker")
+	c.Assert(func() { check.AllOf(check.Equals) }, check.PanicMatches,
+		`AllOf: Equals takes expected arguments, use Bind\(Equals, ...\)`)
+}
+
+func (s *CheckersS) TestAnyOf(c *check.C) {
+	testInfo(c, check.AnyOf(check.IsNil), "AnyOf", []string{"obtained"})
+
+	isMissing := check.AnyOf(check.IsNil, check.Bind(check.ErrorIs, fs.ErrNotExist))
+	testCheck(c, isMissing, true, "", nil)
+	testCheck(c, isMissing, true, "", &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist})
+	testCheck(c, isMissing, false, "No check passed:\n"+
+		"...     IsNil failed\n"+
+		"...     ErrorIs(&errors.errorString{s:\"file does not exist\"}) failed: expected error doesn't contains obtained error\n",
+		fs.ErrExist)
+}
+
+func (s *CheckersS) TestSatisfies(c *check.C) {
+	isEven := check.Satisfies(func(n int) bool { return n%2 == 0 }, "is even")
+	testInfo(c, isEven, "Satisfies", []string{"obtained"})
+
+	testCheck(c, isEven, true, "", 4)
+	testCheck(c, isEven, false, "Predicate not satisfied: is even", 3)
+	testCheck(c, isEven, false, "obtained value is not a int", int64(4))
+	testCheck(c, isEven, false, "obtained value is not a int", nil)
+
+	isTemporary := check.Satisfies(func(err error) bool { return err == nil || errors.Is(err, fs.ErrNotExist) }, "is temporary")
+	testCheck(c, isTemporary, true, "", nil)
+	testCheck(c, isTemporary, false, "Predicate not satisfied: is temporary", fs.ErrClosed)
+	testCheck(c, check.AllOf(isEven, check.Bind(check.MoreThan, 10)), false,
+		"Failed checks:\n...     Satisfies failed: Predicate not satisfied: is even\n", 11)
+}
+
+func (s *CheckersS) TestWithMessage(c *check.C) {
+	checker := check.WithMessage(check.Equals, "the answer is wrong")
+	testInfo(c, checker, "Equals", []string{"obtained", "expected"})
+
+	testCheck(c, checker, true, "", 42, 42)
+	testCheck(c, checker, false, "the answer is wrong", 41, 42)
+	testCheck(c, check.WithMessage(check.Bind(check.InDelta, 1.0, 0.1), "too far"), false,
+		"too far:\n...     Difference: 2 != 1, delta 1 > 0.1\n", 2.0)
+}
+
+func (s *HelpersS) TestCheckCombinedFailure(c *check.C) {
+	log := "(?s)checkers_combinators_test\\.go:[0-9]+:.*\ncheckers_combinators_test\\.go:[0-9]+:\n" +
+		"    return c\\.Check\\(\\[\\]int\\{1\\}, check\\.AllOf\\(check\\.NotNil, check\\.Bind\\(check\\.HasLen, 2\\)\\)\\)\n" +
+		"\\.\\.\\. obtained \\[\\]int = \\[\\]int\\{1\\}\n" +
+		"\\.\\.\\. Failed checks:\n" +
+		"\\.\\.\\.     HasLen\\(2\\) failed\n\n\n"
+	testHelperFailure(c, "Check(AllOf(...))", false, false, log,
+		func() any {
+			return c.Check([]int{1}, check.AllOf(check.NotNil, check.Bind(check.HasLen, 2)))
+		})
+}
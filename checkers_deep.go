@@ -0,0 +1,153 @@
+package check
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/iostrovok/check/pretty"
+)
+
+// -----------------------------------------------------------------------
+// DeepEqualsWith checker.
+
+// A DeepOption customizes how DeepEqualsWith compares values.
+type DeepOption func(opts *deepOptions)
+
+type deepOptions struct {
+	ignored    map[string]bool // Field paths, without indexes nor keys.
+	unexported bool
+	empty      bool
+	transforms []func(v reflect.Value) reflect.Value
+	comparers  []func(av, bv reflect.Value) (equal, ok bool)
+}
+
+// IgnoreFields leaves the named fields out of the comparison.  A field is
+// named by its path from the compared value, with the fields it's nested
+// in separated by dots, whatever the slice indexes and map keys on the way:
+// "Items.ID" is the ID field of every value in the Items field.
+func IgnoreFields(names ...string) DeepOption {
+	return func(opts *deepOptions) {
+		for _, name := range names {
+			opts.ignored[name] = true
+		}
+	}
+}
+
+// IgnoreUnexported leaves unexported struct fields out of the comparison.
+func IgnoreUnexported() DeepOption {
+	return func(opts *deepOptions) {
+		opts.unexported = true
+	}
+}
+
+// EquateEmpty makes nil and empty slices or maps equal.
+func EquateEmpty() DeepOption {
+	return func(opts *deepOptions) {
+		opts.empty = true
+	}
+}
+
+// SortSlices sorts slices of T with less before comparing them, so their
+// order doesn't matter.  The compared values aren't modified.
+func SortSlices[T any](less func(a, b T) bool) DeepOption {
+	sliceType := reflect.TypeOf([]T(nil))
+	return func(opts *deepOptions) {
+		opts.transforms = append(opts.transforms, func(v reflect.Value) reflect.Value {
+			if v.Type() != sliceType || !v.CanInterface() {
+				return v
+			}
+			sorted := append([]T(nil), v.Interface().([]T)...)
+			sort.SliceStable(sorted, func(i, j int) bool { return less(sorted[i], sorted[j]) })
+			return reflect.ValueOf(sorted)
+		})
+	}
+}
+
+// Comparer compares values of type T with equal, rather than field by
+// field, such as to compare time.Time values with their Equal method.
+func Comparer[T any](equal func(a, b T) bool) DeepOption {
+	valueType := reflect.TypeOf((*T)(nil)).Elem()
+	return func(opts *deepOptions) {
+		opts.comparers = append(opts.comparers, func(av, bv reflect.Value) (bool, bool) {
+			if av.Type() != valueType || !av.CanInterface() || !bv.CanInterface() {
+				return false, false
+			}
+			return equal(av.Interface().(T), bv.Interface().(T)), true
+		})
+	}
+}
+
+type deepEqualsWithChecker struct {
+	*CheckerInfo
+	expected any
+	opts     pretty.DiffOptions
+}
+
+// DeepEqualsWith returns a checker which verifies that the obtained value
+// is deep-equal to expected, just like DeepEquals does, but as customized
+// by the given options, such as to leave out fields which can't be known
+// in advance.  On failure, the differences found within the compared
+// fields are reported.
+//
+// For example:
+//
+//	c.Assert(user, DeepEqualsWith(expected, IgnoreFields("ID", "CreatedAt")))
+//	c.Assert(order, DeepEqualsWith(expected, IgnoreFields("Items.ID"),
+//		SortSlices(func(a, b Item) bool { return a.Name < b.Name })))
+func DeepEqualsWith(expected any, options ...DeepOption) Checker {
+	opts := &deepOptions{ignored: make(map[string]bool)}
+	for _, option := range options {
+		option(opts)
+	}
+	return &deepEqualsWithChecker{
+		&CheckerInfo{Name: "DeepEqualsWith", Params: []string{"obtained"}},
+		expected,
+		opts.diffOptions(),
+	}
+}
+
+var pathIndexRegexp = regexp.MustCompile(`\[[^\]]*\]`)
+
+func (opts *deepOptions) diffOptions() pretty.DiffOptions {
+	diffOpts := pretty.DiffOptions{EquateEmpty: opts.empty}
+	if len(opts.ignored) > 0 || opts.unexported {
+		diffOpts.IgnoreField = func(path string, field reflect.StructField) bool {
+			if opts.unexported && !field.IsExported() {
+				return true
+			}
+			// As in "Items.ID" for "Items[0].ID", or "ID" for "[0].ID".
+			return opts.ignored[strings.TrimPrefix(pathIndexRegexp.ReplaceAllString(path, ""), ".")]
+		}
+	}
+	if len(opts.transforms) > 0 {
+		diffOpts.Transform = func(v reflect.Value) reflect.Value {
+			for _, transform := range opts.transforms {
+				v = transform(v)
+			}
+			return v
+		}
+	}
+	if len(opts.comparers) > 0 {
+		diffOpts.Compare = func(av, bv reflect.Value) (bool, bool) {
+			for _, compare := range opts.comparers {
+				if equal, ok := compare(av, bv); ok {
+					return equal, true
+				}
+			}
+			return false, false
+		}
+	}
+	return diffOpts
+}
+
+func (checker *deepEqualsWithChecker) Check(params []any, _ []string) (bool, string) {
+	diff := pretty.DiffWith(params[0], checker.expected, checker.opts)
+	if len(diff) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf(`Difference:
+%s`, formatMultiLine(strings.Join(diff, "\n"), false))
+}
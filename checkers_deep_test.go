@@ -0,0 +1,86 @@
+package check_test
+
+import (
+	"time"
+
+	"github.com/iostrovok/check"
+)
+
+type deepItem struct {
+	ID   int
+	Name string
+}
+
+type deepOrder struct {
+	ID        int
+	CreatedAt time.Time
+	Items     []deepItem
+	Tags      map[string]string
+	note      string
+}
+
+func (s *CheckersS) TestDeepEqualsWith(c *check.C) {
+	testInfo(c, check.DeepEqualsWith(nil), "DeepEqualsWith", []string{"obtained"})
+
+	obtained := &deepOrder{
+		ID:        7,
+		CreatedAt: time.Now(),
+		Items:     []deepItem{{ID: 3, Name: "b"}, {ID: 4, Name: "a"}},
+		note:      "x",
+	}
+	expected := &deepOrder{
+		Items: []deepItem{{Name: "a"}, {Name: "b"}},
+		Tags:  map[string]string{},
+	}
+	testCheck(c, check.DeepEqualsWith(expected, check.IgnoreFields("CreatedAt")), false, "Difference:\n"+
+		"...     ID: 7 != 0\n"+
+		"...     Items[0].ID: 3 != 0\n"+
+		"...     Items[0].Name: \"b\" != \"a\"\n"+
+		"...     Items[1].ID: 4 != 0\n"+
+		"...     Items[1].Name: \"a\" != \"b\"\n"+
+		"...     Tags: map[string]string(nil) != map[string]string{}\n"+
+		"...     note: \"x\" != \"\"\n",
+		obtained)
+
+	byName := check.SortSlices(func(a, b deepItem) bool { return a.Name < b.Name })
+	testCheck(c, check.DeepEqualsWith(expected, check.IgnoreFields("ID", "CreatedAt", "Items.ID"),
+		check.IgnoreUnexported(), check.EquateEmpty(), byName), true, "", obtained)
+	testCheck(c, check.DeepEqualsWith(expected, check.IgnoreFields("ID", "CreatedAt", "Items.ID"),
+		check.IgnoreUnexported(), byName), false, "Difference:\n"+
+		"...     Tags: map[string]string(nil) != map[string]string{}\n",
+		obtained)
+	testCheck(c, check.DeepEqualsWith(expected, check.IgnoreFields("ID", "CreatedAt"),
+		check.IgnoreUnexported(), check.EquateEmpty(), byName), false, "Difference:\n"+
+		"...     Items[0].ID: 4 != 0\n"+
+		"...     Items[1].ID: 3 != 0\n",
+		obtained)
+	testCheck(c, check.DeepEqualsWith(expected, check.IgnoreFields("ID", "CreatedAt", "Items.ID"),
+		check.EquateEmpty()), false, "Difference:\n"+
+		"...     Items[0].Name: \"b\" != \"a\"\n"+
+		"...     Items[1].Name: \"a\" != \"b\"\n"+
+		"...     note: \"x\" != \"\"\n",
+		obtained)
+	c.Assert(obtained.Items[0].Name, check.Equals, "b") // Not sorted in place.
+}
+
+func (s *CheckersS) TestDeepEqualsWithComparer(c *check.C) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	obtained := deepOrder{ID: 1, CreatedAt: at.In(time.FixedZone("X", 3600))}
+	expected := deepOrder{ID: 1, CreatedAt: at}
+	sameInstant := check.Comparer(func(a, b time.Time) bool { return a.Equal(b) })
+
+	testCheck(c, check.DeepEqualsWith(expected, sameInstant), true, "", obtained)
+	testCheckNoLine(c, check.DeepEqualsWith(expected), false, "", obtained)
+
+	expected.CreatedAt = at.Add(time.Second)
+	testCheck(c, check.DeepEqualsWith(expected, sameInstant, check.IgnoreUnexported()), false, "Difference:\n"+
+		"...     CreatedAt: time.Date(2024, time.January, 2, 4, 4, 5, 0, time.Location(\"X\")) != "+
+		"time.Date(2024, time.January, 2, 3, 4, 6, 0, time.UTC)\n",
+		obtained)
+
+	testCheck(c, check.DeepEqualsWith([]int{1}), false, "Difference:\n...     []string != []int\n", []string{"1"})
+	testCheck(c, check.DeepEqualsWith([]int{}), false, "Difference:\n...     []int(nil) != []int{}\n", []int(nil))
+	testCheck(c, check.DeepEqualsWith([]int{}, check.EquateEmpty()), true, "", []int(nil))
+	testCheckNoLine(c, check.DeepEqualsWith(deepOrder{Items: []deepItem{}}), false, "", deepOrder{})
+	testCheckNoLine(c, check.DeepEqualsWith(struct{ s []int }{[]int{}}), false, "", struct{ s []int }{})
+}
@@ -0,0 +1,263 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// -----------------------------------------------------------------------
+// JSONEquals, JSONContains and YAMLEquals checkers.
+
+type documentChecker struct {
+	*CheckerInfo
+	format   string // "JSON" or "YAML".
+	decode   func(text []byte) (any, error)
+	encode   func(value any) ([]byte, error)
+	contains bool
+}
+
+// The JSONEquals checker verifies that the obtained JSON document, given
+// as a string, []byte or json.RawMessage, has the same content as the
+// expected one, whatever the order of keys and the white space.  The
+// expected value may be JSON text as well, or any value encoded as JSON.
+// Differences are reported with their path, as in:
+//
+//	$.items[3].price: 10 != 12
+//
+// For example:
+//
+//	c.Assert(body, JSONEquals, `{"id": 1, "tags": ["a"]}`)
+//	c.Assert(body, JSONEquals, map[string]any{"id": 1, "tags": []string{"a"}})
+var JSONEquals Checker = &documentChecker{
+	CheckerInfo: &CheckerInfo{Name: "JSONEquals", Params: []string{"obtained", "expected"}},
+	format:      "JSON",
+	decode:      decodeJSON,
+	encode:      json.Marshal,
+}
+
+// The JSONContains checker verifies that the obtained JSON document has
+// all the content of the expected one, just like JSONEquals does, but
+// allowing objects to have fields which aren't expected.  Arrays must
+// still have the same length.
+//
+// For example:
+//
+//	c.Assert(body, JSONContains, `{"status": "ok"}`)
+var JSONContains Checker = &documentChecker{
+	CheckerInfo: &CheckerInfo{Name: "JSONContains", Params: []string{"obtained", "expected"}},
+	format:      "JSON",
+	decode:      decodeJSON,
+	encode:      json.Marshal,
+	contains:    true,
+}
+
+// The YAMLEquals checker verifies that the obtained YAML document has the
+// same content as the expected one, just like JSONEquals does for JSON.
+//
+// For example:
+//
+//	c.Assert(config, YAMLEquals, "name: x\nports: [80, 443]\n")
+var YAMLEquals Checker = &documentChecker{
+	CheckerInfo: &CheckerInfo{Name: "YAMLEquals", Params: []string{"obtained", "expected"}},
+	format:      "YAML",
+	decode:      decodeYAML,
+	encode:      yaml.Marshal,
+}
+
+func decodeJSON(text []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the document")
+	}
+	return value, nil
+}
+
+func decodeYAML(text []byte) (any, error) {
+	var value any
+	if err := yaml.Unmarshal(text, &value); err != nil {
+		return nil, err
+	}
+	return normalizeYAML(value), nil
+}
+
+// normalizeYAML turns a decoded YAML document into the values a decoded
+// JSON document would have, so they may be compared the same way.
+func normalizeYAML(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, e := range v {
+			v[key] = normalizeYAML(e)
+		}
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for key, e := range v {
+			m[fmt.Sprint(key)] = normalizeYAML(e)
+		}
+		return m
+	case []any:
+		for i, e := range v {
+			v[i] = normalizeYAML(e)
+		}
+	case int, int64, uint64, float64:
+		return json.Number(fmt.Sprint(v))
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return value
+}
+
+// document decodes the given value, which is the text of a document unless
+// encode is given, in which case values of other types are encoded first.
+func (checker *documentChecker) document(value any, encode bool) (any, error) {
+	var text []byte
+	switch v := value.(type) {
+	case string:
+		text = []byte(v)
+	case []byte:
+		text = v
+	case json.RawMessage:
+		text = v
+	default:
+		if !encode {
+			return nil, fmt.Errorf("must be a string, []byte or json.RawMessage")
+		}
+		var err error
+		if text, err = checker.encode(value); err != nil {
+			return nil, err
+		}
+	}
+	return checker.decode(text)
+}
+
+func (checker *documentChecker) Check(params []any, names []string) (result bool, error string) {
+	obtained, err := checker.document(params[0], false)
+	if err != nil {
+		return false, fmt.Sprintf("Cannot decode obtained %s: %v", checker.format, err)
+	}
+	expected, err := checker.document(params[1], true)
+	if err != nil {
+		return false, fmt.Sprintf("Cannot decode expected %s: %v", checker.format, err)
+	}
+	d := documentDiff{contains: checker.contains}
+	d.compare("$", obtained, expected)
+	if len(d.diffs) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf(`%s difference:
+%s`, checker.format, formatMultiLine(strings.Join(d.diffs, "\n"), false))
+}
+
+// documentDiff collects the differences between decoded documents.
+type documentDiff struct {
+	contains bool
+	diffs    []string
+}
+
+func (d *documentDiff) add(path, format string, args ...any) {
+	d.diffs = append(d.diffs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *documentDiff) compare(path string, obtained, expected any) {
+	switch e := expected.(type) {
+	case map[string]any:
+		o, ok := obtained.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(e) {
+			if value, ok := o[key]; ok {
+				d.compare(documentPath(path, key), value, e[key])
+			} else {
+				d.add(documentPath(path, key), "missing, expected %s", documentText(e[key]))
+			}
+		}
+		if !d.contains {
+			for _, key := range sortedKeys(o) {
+				if _, ok := e[key]; !ok {
+					d.add(documentPath(path, key), "unexpected %s", documentText(o[key]))
+				}
+			}
+		}
+		return
+	case []any:
+		o, ok := obtained.([]any)
+		if !ok {
+			break
+		}
+		for i := 0; i < len(o) || i < len(e); i++ {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(o):
+				d.add(itemPath, "missing, expected %s", documentText(e[i]))
+			case i >= len(e):
+				d.add(itemPath, "unexpected %s", documentText(o[i]))
+			default:
+				d.compare(itemPath, o[i], e[i])
+			}
+		}
+		return
+	case json.Number:
+		if o, ok := obtained.(json.Number); ok && numbersEqual(o, e) {
+			return
+		}
+	default:
+		if obtained == expected {
+			return
+		}
+	}
+	d.add(path, "%s != %s", documentText(obtained), documentText(expected))
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func numbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	x, okx := new(big.Float).SetString(string(a))
+	y, oky := new(big.Float).SetString(string(b))
+	return okx && oky && x.Cmp(y) == 0
+}
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// documentPath returns the path of the given key within the object at path,
+// as in "$.items" or `$["odd key"]`.
+func documentPath(path, key string) string {
+	if identifierRegexp.MatchString(key) {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// documentText returns the compact JSON text of a decoded value.
+func documentText(value any) string {
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(out.String(), "\n")
+}
@@ -0,0 +1,62 @@
+package check_test
+
+import (
+	"encoding/json"
+
+	"github.com/iostrovok/check"
+)
+
+func (s *CheckersS) TestJSONEquals(c *check.C) {
+	testInfo(c, check.JSONEquals, "JSONEquals", []string{"obtained", "expected"})
+
+	testCheck(c, check.JSONEquals, true, "", `{"a": 1, "b": [true, null, "x"]}`, `{"b":[true,null,"x"],"a":1}`)
+	testCheck(c, check.JSONEquals, true, "", []byte(`{"a": 1.0}`), `{"a": 1}`)
+	testCheck(c, check.JSONEquals, true, "", json.RawMessage(`[1]`), []int{1})
+	testCheck(c, check.JSONEquals, true, "", `{"id": 1, "name": "x"}`, struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}{1, "x"})
+	testCheck(c, check.JSONEquals, true, "", `12345678901234567890`, `12345678901234567890.0`)
+
+	testCheck(c, check.JSONEquals, false, "JSON difference:\n"+
+		"...     $.items[1].price: 10 != 12\n"+
+		"...     $.items[1].tags: missing, expected [\"<new>\"]\n"+
+		"...     $.items[2]: unexpected {}\n"+
+		"...     $[\"odd key\"]: \"a\" != \"b\"\n"+
+		"...     $.total: unexpected 22\n",
+		`{"items": [{}, {"price": 10}, {}], "odd key": "a", "total": 22}`,
+		`{"items": [{}, {"price": 12, "tags": ["<new>"]}], "odd key": "b"}`)
+	testCheck(c, check.JSONEquals, false, "JSON difference:\n...     $: 1 != \"1\"\n", `1`, `"1"`)
+	testCheck(c, check.JSONEquals, false, "JSON difference:\n...     $.a: [1] != {\"b\":1}\n", `{"a": [1]}`, `{"a": {"b": 1}}`)
+
+	testCheck(c, check.JSONEquals, false, "Cannot decode obtained JSON: must be a string, []byte or json.RawMessage", 1, `1`)
+	testCheck(c, check.JSONEquals, false, "Cannot decode obtained JSON: unexpected EOF", `{"a":`, `1`)
+	testCheck(c, check.JSONEquals, false, "Cannot decode obtained JSON: unexpected data after the document", `1 2`, `1`)
+	testCheck(c, check.JSONEquals, false, "Cannot decode expected JSON: invalid character 'x' looking for beginning of value", `1`, `x`)
+}
+
+func (s *CheckersS) TestJSONContains(c *check.C) {
+	testInfo(c, check.JSONContains, "JSONContains", []string{"obtained", "expected"})
+
+	obtained := `{"status": "ok", "data": {"id": 7, "items": [{"id": 1, "price": 3}], "next": null}}`
+	testCheck(c, check.JSONContains, true, "", obtained, `{"status": "ok"}`)
+	testCheck(c, check.JSONContains, true, "", obtained, `{"data": {"items": [{"price": 3}]}}`)
+	testCheck(c, check.JSONContains, true, "", obtained, map[string]any{"data": map[string]any{"next": nil}})
+
+	testCheck(c, check.JSONContains, false, "JSON difference:\n"+
+		"...     $.data.id: 7 != 8\n"+
+		"...     $.data.items[1]: missing, expected {}\n"+
+		"...     $.error: missing, expected null\n",
+		obtained, `{"data": {"id": 8, "items": [{}, {}]}, "error": null}`)
+}
+
+func (s *CheckersS) TestYAMLEquals(c *check.C) {
+	testInfo(c, check.YAMLEquals, "YAMLEquals", []string{"obtained", "expected"})
+
+	testCheck(c, check.YAMLEquals, true, "", "name: x\nports: [80, 443]\n", "ports:\n  - 80\n  - 443\nname: x\n")
+	testCheck(c, check.YAMLEquals, true, "", "1: one\nratio: 0.5\n", map[string]any{"1": "one", "ratio": 0.5})
+	testCheck(c, check.YAMLEquals, false, "YAML difference:\n"+
+		"...     $.ports[1]: 443 != 8443\n"+
+		"...     $.tls: missing, expected true\n",
+		"name: x\nports: [80, 443]\n", "name: x\nports: [80, 8443]\ntls: true\n")
+}
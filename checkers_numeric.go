@@ -0,0 +1,203 @@
+package check
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// -----------------------------------------------------------------------
+// InDelta, InEpsilon and WithinULP checkers.
+
+type toleranceChecker struct {
+	*CheckerInfo
+	integral bool // The tolerance must be a whole number.
+	compare  func(obtained, expected number, tolerance float64) (bool, string)
+}
+
+// The InDelta checker verifies that the obtained number is within delta of
+// the expected one, that is |obtained - expected| <= delta.  Numbers may be
+// of any integer or floating point kind.  Slices, arrays and maps of numbers
+// are compared element by element, and all the elements out of tolerance
+// are reported.
+//
+// For example:
+//
+//	c.Assert(mean, InDelta, 2.5, 1e-9)
+//	c.Assert(weights, InDelta, []float64{0.1, 0.9}, 0.01)
+var InDelta Checker = &toleranceChecker{
+	CheckerInfo: &CheckerInfo{Name: "InDelta", Params: []string{"obtained", "expected", "delta"}},
+	compare:     inDelta,
+}
+
+// The InEpsilon checker verifies that the relative error of the obtained
+// number, |obtained - expected| / |expected|, is at most epsilon.  Numbers
+// are handled just like InDelta does.  An expected 0 only matches an
+// obtained 0, since the relative error is undefined then.
+//
+// For example:
+//
+//	c.Assert(total, InEpsilon, 1000, 0.01) // Within 1%.
+var InEpsilon Checker = &toleranceChecker{
+	CheckerInfo: &CheckerInfo{Name: "InEpsilon", Params: []string{"obtained", "expected", "epsilon"}},
+	compare:     inEpsilon,
+}
+
+// The WithinULP checker verifies that the obtained floating point number
+// is at most the given number of units in the last place away from the
+// expected one, that is the number of representable values between them.
+// The distance is measured between float32 values when either number is a
+// float32, and between float64 values otherwise.  Numbers are handled just
+// like InDelta does.
+//
+// For example:
+//
+//	c.Assert(math.Sqrt(x)*math.Sqrt(x), WithinULP, x, 2)
+var WithinULP Checker = &toleranceChecker{
+	CheckerInfo: &CheckerInfo{Name: "WithinULP", Params: []string{"obtained", "expected", "ulps"}},
+	integral:    true,
+	compare:     withinULP,
+}
+
+// number is a numeric value, with the precision it had.
+type number struct {
+	value   float64
+	float32 bool
+}
+
+// toNumber goes through reflect rather than a type switch, so that named
+// numeric types, such as time.Duration, are numbers too.
+func toNumber(value any) (number, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return number{value: float64(v.Int())}, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return number{value: float64(v.Uint())}, true
+	case reflect.Float64:
+		return number{value: v.Float()}, true
+	case reflect.Float32:
+		return number{value: v.Float(), float32: true}, true
+	}
+	return number{}, false
+}
+
+func inDelta(obtained, expected number, delta float64) (bool, string) {
+	d := math.Abs(obtained.value - expected.value)
+	return d <= delta, fmt.Sprintf("delta %v > %v", d, delta)
+}
+
+func inEpsilon(obtained, expected number, epsilon float64) (bool, string) {
+	if expected.value == 0 {
+		return obtained.value == 0, "relative error is undefined for an expected 0"
+	}
+	e := math.Abs(obtained.value-expected.value) / math.Abs(expected.value)
+	return e <= epsilon, fmt.Sprintf("relative error %v > %v", e, epsilon)
+}
+
+func withinULP(obtained, expected number, ulps float64) (bool, string) {
+	if math.IsNaN(obtained.value) || math.IsNaN(expected.value) {
+		return false, "ULPs are undefined for NaN"
+	}
+	var n uint64
+	if obtained.float32 || expected.float32 {
+		n = ulpDistance(orderedFloat32(float32(obtained.value)), orderedFloat32(float32(expected.value)))
+	} else {
+		n = ulpDistance(orderedFloat64(obtained.value), orderedFloat64(expected.value))
+	}
+	return float64(n) <= ulps, fmt.Sprintf("%d ULPs apart > %v", n, ulps)
+}
+
+// orderedFloat64 maps f to an integer, so that consecutive floating point
+// values map to consecutive integers.
+func orderedFloat64(f float64) int64 {
+	i := int64(math.Float64bits(f))
+	if i < 0 {
+		i = math.MinInt64 - i
+	}
+	return i
+}
+
+func orderedFloat32(f float32) int64 {
+	i := int32(math.Float32bits(f))
+	if i < 0 {
+		i = math.MinInt32 - i
+	}
+	return int64(i)
+}
+
+func ulpDistance(a, b int64) uint64 {
+	if a > b {
+		return uint64(a) - uint64(b)
+	}
+	return uint64(b) - uint64(a)
+}
+
+func (checker *toleranceChecker) Check(params []any, names []string) (bool, string) {
+	tolerance, ok := toNumber(params[2])
+	if !ok || !(tolerance.value >= 0) || checker.integral && tolerance.value != math.Trunc(tolerance.value) {
+		kind := "number"
+		if checker.integral {
+			kind = "whole number"
+		}
+		return false, fmt.Sprintf("%s must be a non-negative %s", names[2], kind)
+	}
+
+	obtained, expected := reflect.ValueOf(params[0]), reflect.ValueOf(params[1])
+	if !isList(obtained) && obtained.Kind() != reflect.Map {
+		return checker.compareNumbers(params[0], params[1], tolerance.value)
+	}
+
+	var lines []string
+	add := func(path string, obtained, expected any) {
+		if ok, message := checker.compareNumbers(obtained, expected, tolerance.value); !ok {
+			lines = append(lines, path+": "+message)
+		}
+	}
+	switch {
+	case isList(obtained) && isList(expected):
+		if obtained.Len() != expected.Len() {
+			return false, fmt.Sprintf("obtained and expected have different lengths: %d != %d", obtained.Len(), expected.Len())
+		}
+		for i := 0; i < obtained.Len(); i++ {
+			add(fmt.Sprintf("[%d]", i), obtained.Index(i).Interface(), expected.Index(i).Interface())
+		}
+	case obtained.Kind() == reflect.Map && expected.Kind() == reflect.Map:
+		for _, key := range sortedMapKeys(obtained) {
+			path := fmt.Sprintf("[%#v]", key.Interface())
+			if expectedKey, found := findMapKey(expected, key.Interface()); found {
+				add(path, obtained.MapIndex(key).Interface(), expected.MapIndex(expectedKey).Interface())
+			} else {
+				lines = append(lines, path+": unexpected")
+			}
+		}
+		for _, key := range sortedMapKeys(expected) {
+			if _, found := findMapKey(obtained, key.Interface()); !found {
+				lines = append(lines, fmt.Sprintf("[%#v]: missing", key.Interface()))
+			}
+		}
+	default:
+		return false, "obtained and expected values must be both slices or arrays, or both maps"
+	}
+	if len(lines) == 0 {
+		return true, ""
+	}
+	return false, fmt.Sprintf(`Differences:
+%s`, formatMultiLine(strings.Join(lines, "\n"), false))
+}
+
+func (checker *toleranceChecker) compareNumbers(obtained, expected any, tolerance float64) (bool, string) {
+	o, ok := toNumber(obtained)
+	if !ok {
+		return false, "obtained value is not a number"
+	}
+	e, ok := toNumber(expected)
+	if !ok {
+		return false, "expected value is not a number"
+	}
+	if ok, message := checker.compare(o, e, tolerance); !ok {
+		return false, fmt.Sprintf("Difference: %v != %v, %s", obtained, expected, message)
+	}
+	return true, ""
+}
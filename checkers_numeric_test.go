@@ -0,0 +1,77 @@
+package check_test
+
+import (
+	"math"
+	"time"
+
+	"github.com/iostrovok/check"
+)
+
+func (s *CheckersS) TestInDelta(c *check.C) {
+	testInfo(c, check.InDelta, "InDelta", []string{"obtained", "expected", "delta"})
+
+	a, b := 0.1, 0.2
+	testCheck(c, check.InDelta, true, "", a+b, 0.3, 1e-9)
+	testCheck(c, check.InDelta, true, "", 42, int64(44), 2)
+	testCheck(c, check.InDelta, true, "", uint8(10), float32(10.5), 0.5)
+	testCheck(c, check.InDelta, true, "", 1.0, 1.0, 0)
+	testCheck(c, check.InDelta, true, "", 5*time.Second, 5*time.Second+time.Millisecond, time.Millisecond)
+	testCheck(c, check.InDelta, false, "Difference: 5s != 0s, delta 5e+09 > 1", 5*time.Second, time.Duration(0), 1)
+	testCheck(c, check.InDelta, true, "", celsius(36.6), celsius(37), 0.5)
+	testCheck(c, check.InDelta, false, "Difference: 100 != 0, delta 100 > 1", celsius(100), celsius(0), 1)
+	testCheck(c, check.InDelta, false, "Difference: 1.5 != 1, delta 0.5 > 0.25", 1.5, 1, 0.25)
+	testCheck(c, check.InDelta, false, "Difference: NaN != 1, delta NaN > 1", math.NaN(), 1, 1)
+
+	testCheck(c, check.InDelta, true, "", []float64{1, 2.05}, [2]int{1, 2}, 0.1)
+	testCheck(c, check.InDelta, false, "Differences:\n"+
+		"...     [1]: Difference: 2.5 != 2, delta 0.5 > 0.1\n"+
+		"...     [2]: obtained value is not a number\n",
+		[]any{1.0, 2.5, "x"}, []float64{1, 2, 3}, 0.1)
+	testCheck(c, check.InDelta, true, "", map[string]float64{"a": 1.01}, map[string]int{"a": 1}, 0.1)
+	testCheck(c, check.InDelta, false, "Differences:\n"+
+		"...     [\"a\"]: Difference: 2 != 1, delta 1 > 0.1\n"+
+		"...     [\"c\"]: unexpected\n"+
+		"...     [\"b\"]: missing\n",
+		map[string]float64{"a": 2, "c": 3}, map[string]float64{"a": 1, "b": 2}, 0.1)
+
+	testCheck(c, check.InDelta, false, "obtained and expected have different lengths: 1 != 2", []int{1}, []int{1, 2}, 1)
+	testCheck(c, check.InDelta, false, "obtained and expected values must be both slices or arrays, or both maps",
+		[]int{1}, map[int]int{0: 1}, 1)
+	testCheck(c, check.InDelta, false, "obtained value is not a number", "1", 1, 1)
+	testCheck(c, check.InDelta, false, "expected value is not a number", 1, nil, 1)
+	testCheck(c, check.InDelta, false, "delta must be a non-negative number", 1, 1, -1)
+	testCheck(c, check.InDelta, false, "delta must be a non-negative number", 1, 1, "1")
+	testCheck(c, check.InDelta, false, "delta must be a non-negative number", 1, 1, math.NaN())
+}
+
+func (s *CheckersS) TestInEpsilon(c *check.C) {
+	testInfo(c, check.InEpsilon, "InEpsilon", []string{"obtained", "expected", "epsilon"})
+
+	testCheck(c, check.InEpsilon, true, "", 1010, 1000, 0.01)
+	testCheck(c, check.InEpsilon, true, "", -99.5, -100, 0.01)
+	testCheck(c, check.InEpsilon, true, "", 0, 0.0, 0.01)
+	testCheck(c, check.InEpsilon, false, "Difference: 1100 != 1000, relative error 0.1 > 0.01", 1100, 1000, 0.01)
+	testCheck(c, check.InEpsilon, false, "Difference: 1e-12 != 0, relative error is undefined for an expected 0", 1e-12, 0, 0.01)
+	testCheck(c, check.InEpsilon, false, "Differences:\n...     [0]: Difference: 2 != 1, relative error 1 > 0.5\n",
+		[]int{2, 4}, []int{1, 4}, 0.5)
+	testCheck(c, check.InEpsilon, false, "Difference: 2s != 1s, relative error 1 > 0.01", 2*time.Second, time.Second, 0.01)
+	testCheck(c, check.InEpsilon, false, "Difference: 100 != 50, relative error 1 > 0.01", celsius(100), celsius(50), 0.01)
+	testCheck(c, check.InEpsilon, false, "epsilon must be a non-negative number", 1, 1, -0.1)
+}
+
+func (s *CheckersS) TestWithinULP(c *check.C) {
+	testInfo(c, check.WithinULP, "WithinULP", []string{"obtained", "expected", "ulps"})
+
+	a, b := 0.1, 0.2
+	testCheck(c, check.WithinULP, true, "", a+b, 0.3, 1)
+	testCheck(c, check.WithinULP, false, "Difference: 0.30000000000000004 != 0.3, 1 ULPs apart > 0", a+b, 0.3, 0)
+	testCheck(c, check.WithinULP, true, "", float32(a)+float32(b), 0.3, 0)
+	testCheck(c, check.WithinULP, true, "", math.Nextafter(0, 1), math.Nextafter(0, -1), 2)
+	testCheck(c, check.WithinULP, true, "", math.Inf(1), math.Inf(1), 0)
+	testCheck(c, check.WithinULP, false, "Difference: NaN != NaN, ULPs are undefined for NaN", math.NaN(), math.NaN(), 0)
+	testCheck(c, check.WithinULP, false, "Difference: 1 != NaN, ULPs are undefined for NaN", 1.0, math.NaN(), 1e9)
+	testCheck(c, check.WithinULP, true, "", []float32{1, 2}, []float32{1, math.Nextafter32(2, 3)}, 1)
+	testCheck(c, check.WithinULP, true, "", celsius(a+b), celsius(0.3), 1)
+	testCheck(c, check.WithinULP, false, "Difference: 1 != 0, 4607182418800017408 ULPs apart > 1", celsius(1), celsius(0), 1)
+	testCheck(c, check.WithinULP, false, "ulps must be a non-negative whole number", 1.0, 1.0, 0.5)
+}
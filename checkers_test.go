@@ -646,6 +646,77 @@ func (s *CheckersS) TestNotContains(c *check.C) {
 	testCheck(c, check.NotContains, true, "", &A{b: 20, a: 10}, []*A{{a: 5, b: 16}, {a: 1, b: 0}, {a: 5, b: 16}})
 }
 
+func (s *CheckersS) TestElementsMatch(c *check.C) {
+	testInfo(c, check.ElementsMatch, "ElementsMatch", []string{"obtained", "expected"})
+
+	testCheck(c, check.ElementsMatch, true, "", []int{1, 2, 2, 3}, []int{2, 3, 1, 2})
+	testCheck(c, check.ElementsMatch, true, "", [3]string{"a", "b", "c"}, []string{"c", "a", "b"})
+	testCheck(c, check.ElementsMatch, true, "", []any{1, "a"}, []any{"a", 1})
+	testCheck(c, check.ElementsMatch, true, "", []int{}, []int(nil))
+	testCheck(c, check.ElementsMatch, true, "", [][]int{{1}, {2}}, [][]int{{2}, {1}})
+
+	testCheck(c, check.ElementsMatch, false, "Elements differ:\n"+
+		"...     missing: \"c\" (2 times)\n"+
+		"...     missing: \"d\"\n"+
+		"...     extra: \"a\"\n",
+		[]string{"a", "a", "b"}, []string{"c", "b", "a", "d", "c"})
+	testCheck(c, check.ElementsMatch, false, "Elements differ:\n...     extra: 2 (2 times)\n", []int{1, 2, 2}, []int{1})
+
+	testCheck(c, check.ElementsMatch, false, "obtained value is not a slice or array", map[int]int{}, []int{})
+	testCheck(c, check.ElementsMatch, false, "expected value is not a slice or array", []int{}, "a")
+}
+
+func (s *CheckersS) TestIsSubsetOf(c *check.C) {
+	testInfo(c, check.IsSubsetOf, "IsSubsetOf", []string{"obtained", "expected"})
+
+	testCheck(c, check.IsSubsetOf, true, "", []int{3, 1, 1}, []int{1, 2, 3})
+	testCheck(c, check.IsSubsetOf, true, "", []string(nil), [1]string{"a"})
+	testCheck(c, check.IsSubsetOf, true, "", map[string]int{"a": 1}, map[string]any{"a": 1, "b": "x"})
+
+	testCheck(c, check.IsSubsetOf, false, "Not a subset:\n...     unexpected: 4\n...     unexpected: 5\n",
+		[]int{1, 4, 5}, []int{1, 2, 3})
+	testCheck(c, check.IsSubsetOf, false, "Not a subset:\n"+
+		"...     key \"a\": 1 != 2\n"+
+		"...     unexpected key \"c\": 3\n",
+		map[string]int{"c": 3, "a": 1, "b": 2}, map[string]int{"a": 2, "b": 2})
+
+	testCheck(c, check.IsSubsetOf, false, "obtained and expected values must be both slices or arrays, or both maps",
+		[]int{1}, map[int]int{1: 1})
+}
+
+func (s *CheckersS) TestIsSupersetOf(c *check.C) {
+	testInfo(c, check.IsSupersetOf, "IsSupersetOf", []string{"obtained", "expected"})
+
+	testCheck(c, check.IsSupersetOf, true, "", []int{1, 2, 3}, []int{3, 1})
+	testCheck(c, check.IsSupersetOf, true, "", map[string]int{"a": 1, "b": 2}, map[string]int{"b": 2})
+
+	testCheck(c, check.IsSupersetOf, false, "Not a superset:\n...     missing: \"c\"\n",
+		[]string{"a", "b"}, []string{"b", "c"})
+	testCheck(c, check.IsSupersetOf, false, "Not a superset:\n"+
+		"...     key 1: \"x\" != \"y\"\n"+
+		"...     missing key 2: \"z\"\n",
+		map[int]string{1: "x", 3: "w"}, map[int]string{2: "z", 1: "y"})
+}
+
+func (s *CheckersS) TestHasKeys(c *check.C) {
+	testInfo(c, check.HasKeys, "HasKeys", []string{"obtained", "expected"})
+
+	testCheck(c, check.HasKeys, true, "", map[string]int{"a": 1, "b": 2}, []string{"b", "a"})
+	testCheck(c, check.HasKeys, true, "", map[string]int{}, []string{})
+	testCheck(c, check.HasKeys, false, "Missing keys:\n...     \"c\"\n...     \"d\"\n",
+		map[string]int{"a": 1}, []string{"a", "c", "d"})
+
+	testCheck(c, check.HasKeys, false, "obtained value is not a map", []int{1}, []int{1})
+	testCheck(c, check.HasKeys, false, "expected value is not a slice or array", map[string]int{}, "a")
+}
+
+func (s *CheckersS) TestHasValues(c *check.C) {
+	testInfo(c, check.HasValues, "HasValues", []string{"obtained", "expected"})
+
+	testCheck(c, check.HasValues, true, "", map[string]int{"a": 1, "b": 2}, []int{2, 1})
+	testCheck(c, check.HasValues, false, "Missing values:\n...     3\n", map[string]int{"a": 1}, [2]int{1, 3})
+}
+
 func (s *CheckersS) TestErrorIs(c *check.C) {
 	e1 := errors.New("my error")
 	testCheck(c, check.ErrorIs, false, "expected value is not an error", e1, 1)
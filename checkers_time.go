@@ -0,0 +1,194 @@
+package check
+
+import (
+	"fmt"
+	"time"
+)
+
+// -----------------------------------------------------------------------
+// TimeEquals, WithinDuration, IsBefore, IsAfter and DurationBetween checkers.
+
+// formatTime formats t without its monotonic clock reading, which would
+// make otherwise equal times look different.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// timeParams returns the obtained and expected values as times, if both
+// are.
+func timeParams(params []any) (obtained, expected time.Time, ok bool) {
+	obtained, ok = params[0].(time.Time)
+	if ok {
+		expected, ok = params[1].(time.Time)
+	}
+	return
+}
+
+// durationParams returns the obtained and expected values as durations,
+// if both are.
+func durationParams(params []any) (obtained, expected time.Duration, ok bool) {
+	obtained, ok = params[0].(time.Duration)
+	if ok {
+		expected, ok = params[1].(time.Duration)
+	}
+	return
+}
+
+// checkTimeParams is like timeParams, but describes what's wrong too.
+func checkTimeParams(params []any, names []string) (obtained, expected time.Time, error string) {
+	var ok bool
+	if obtained, ok = params[0].(time.Time); !ok {
+		return obtained, expected, names[0] + " value is not a time.Time"
+	}
+	if expected, ok = params[1].(time.Time); !ok {
+		return obtained, expected, names[1] + " value is not a time.Time"
+	}
+	return obtained, expected, ""
+}
+
+type timeEquals struct {
+	*CheckerInfo
+}
+
+// The TimeEquals checker verifies that the obtained time is the same
+// instant as the expected one, whatever their locations and monotonic
+// clock readings.
+//
+// For example:
+//
+//	c.Assert(event.At, TimeEquals, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+var TimeEquals Checker = &timeEquals{
+	&CheckerInfo{Name: "TimeEquals", Params: []string{"obtained", "expected"}},
+}
+
+func (checker *timeEquals) Check(params []any, names []string) (bool, string) {
+	obtained, expected, error := checkTimeParams(params, names)
+	if error != "" {
+		return false, error
+	}
+	if obtained.Equal(expected) {
+		return true, ""
+	}
+	return false, fmt.Sprintf("Difference: %s != %s, %s", formatTime(obtained), formatTime(expected),
+		timeDistance(obtained, expected))
+}
+
+// timeDistance describes how far the obtained time is from the expected
+// one, as in "1.5s later".
+func timeDistance(obtained, expected time.Time) string {
+	d := obtained.Sub(expected)
+	if d < 0 {
+		return fmt.Sprintf("%v earlier", -d)
+	}
+	return fmt.Sprintf("%v later", d)
+}
+
+type withinDuration struct {
+	*CheckerInfo
+}
+
+// The WithinDuration checker verifies that the obtained time is at most
+// tolerance away from the expected one, before or after it.
+//
+// For example:
+//
+//	c.Assert(user.CreatedAt, WithinDuration, time.Now(), time.Second)
+var WithinDuration Checker = &withinDuration{
+	&CheckerInfo{Name: "WithinDuration", Params: []string{"obtained", "expected", "tolerance"}},
+}
+
+func (checker *withinDuration) Check(params []any, names []string) (bool, string) {
+	obtained, expected, error := checkTimeParams(params, names)
+	if error != "" {
+		return false, error
+	}
+	tolerance, ok := params[2].(time.Duration)
+	if !ok || tolerance < 0 {
+		return false, "tolerance must be a non-negative time.Duration"
+	}
+	d := obtained.Sub(expected)
+	if d >= -tolerance && d <= tolerance {
+		return true, ""
+	}
+	return false, fmt.Sprintf("Difference: %s != %s, %s > %v", formatTime(obtained), formatTime(expected),
+		timeDistance(obtained, expected), tolerance)
+}
+
+type timeOrder struct {
+	*CheckerInfo
+	after bool
+}
+
+// The IsBefore checker verifies that the obtained time is strictly before
+// the expected one.
+//
+// For example:
+//
+//	c.Assert(job.StartedAt, IsBefore, job.FinishedAt)
+var IsBefore Checker = &timeOrder{
+	&CheckerInfo{Name: "IsBefore", Params: []string{"obtained", "expected"}},
+	false,
+}
+
+// The IsAfter checker verifies that the obtained time is strictly after
+// the expected one.
+//
+// For example:
+//
+//	c.Assert(token.ExpiresAt, IsAfter, time.Now())
+var IsAfter Checker = &timeOrder{
+	&CheckerInfo{Name: "IsAfter", Params: []string{"obtained", "expected"}},
+	true,
+}
+
+func (checker *timeOrder) Check(params []any, names []string) (bool, string) {
+	obtained, expected, error := checkTimeParams(params, names)
+	if error != "" {
+		return false, error
+	}
+	if checker.after && obtained.After(expected) || !checker.after && obtained.Before(expected) {
+		return true, ""
+	}
+	expect := "expect before"
+	if checker.after {
+		expect = "expect after"
+	}
+	if obtained.Equal(expected) {
+		return false, fmt.Sprintf("Difference: %s equals %s, %s", formatTime(obtained), formatTime(expected), expect)
+	}
+	return false, fmt.Sprintf("Difference: %s is %s than %s, %s", formatTime(obtained),
+		timeDistance(obtained, expected), formatTime(expected), expect)
+}
+
+type durationBetween struct {
+	*CheckerInfo
+}
+
+// The DurationBetween checker verifies that the obtained duration is
+// within the given bounds, both included.
+//
+// For example:
+//
+//	c.Assert(time.Since(start), DurationBetween, 100*time.Millisecond, time.Second)
+var DurationBetween Checker = &durationBetween{
+	&CheckerInfo{Name: "DurationBetween", Params: []string{"obtained", "min", "max"}},
+}
+
+func (checker *durationBetween) Check(params []any, names []string) (bool, string) {
+	var durations [3]time.Duration
+	for i, param := range params {
+		d, ok := param.(time.Duration)
+		if !ok {
+			return false, names[i] + " value is not a time.Duration"
+		}
+		durations[i] = d
+	}
+	obtained, min, max := durations[0], durations[1], durations[2]
+	if min > max {
+		return false, "min must not be more than max"
+	}
+	if obtained >= min && obtained <= max {
+		return true, ""
+	}
+	return false, fmt.Sprintf("Difference: %v is not between %v and %v", obtained, min, max)
+}
@@ -0,0 +1,74 @@
+package check_test
+
+import (
+	"time"
+
+	"github.com/iostrovok/check"
+)
+
+var (
+	timeA = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	timeB = timeA.Add(1500 * time.Millisecond)
+)
+
+func (s *CheckersS) TestTimeEquals(c *check.C) {
+	testInfo(c, check.TimeEquals, "TimeEquals", []string{"obtained", "expected"})
+
+	now := time.Now()
+	testCheck(c, check.TimeEquals, true, "", now, now.Round(0))
+	testCheck(c, check.TimeEquals, true, "", timeA, timeA.In(time.FixedZone("X", 3600)))
+	testCheck(c, check.TimeEquals, false, "Difference: 2024-01-02T03:04:06.5Z != 2024-01-02T03:04:05Z, 1.5s later", timeB, timeA)
+	testCheck(c, check.TimeEquals, false, "Difference: 2024-01-02T03:04:05Z != 2024-01-02T03:04:06.5Z, 1.5s earlier", timeA, timeB)
+	testCheck(c, check.TimeEquals, false, "obtained value is not a time.Time", "2024", timeA)
+	testCheck(c, check.TimeEquals, false, "expected value is not a time.Time", timeA, &timeA)
+}
+
+func (s *CheckersS) TestWithinDuration(c *check.C) {
+	testInfo(c, check.WithinDuration, "WithinDuration", []string{"obtained", "expected", "tolerance"})
+
+	testCheck(c, check.WithinDuration, true, "", timeA, timeB, 2*time.Second)
+	testCheck(c, check.WithinDuration, true, "", timeB, timeA, 1500*time.Millisecond)
+	testCheck(c, check.WithinDuration, false, "Difference: 2024-01-02T03:04:05Z != 2024-01-02T03:04:06.5Z, 1.5s earlier > 1s",
+		timeA, timeB, time.Second)
+	testCheck(c, check.WithinDuration, false, "tolerance must be a non-negative time.Duration", timeA, timeB, 1)
+	testCheck(c, check.WithinDuration, false, "tolerance must be a non-negative time.Duration", timeA, timeB, -time.Second)
+}
+
+func (s *CheckersS) TestIsBeforeIsAfter(c *check.C) {
+	testInfo(c, check.IsBefore, "IsBefore", []string{"obtained", "expected"})
+	testInfo(c, check.IsAfter, "IsAfter", []string{"obtained", "expected"})
+
+	testCheck(c, check.IsBefore, true, "", timeA, timeB)
+	testCheck(c, check.IsBefore, false, "Difference: 2024-01-02T03:04:06.5Z is 1.5s later than 2024-01-02T03:04:05Z, expect before",
+		timeB, timeA)
+	testCheck(c, check.IsBefore, false, "Difference: 2024-01-02T03:04:05Z equals 2024-01-02T03:04:05Z, expect before", timeA, timeA)
+
+	testCheck(c, check.IsAfter, true, "", timeB, timeA)
+	testCheck(c, check.IsAfter, false, "Difference: 2024-01-02T03:04:05Z is 1.5s earlier than 2024-01-02T03:04:06.5Z, expect after",
+		timeA, timeB)
+	testCheck(c, check.IsAfter, false, "obtained value is not a time.Time", 1, timeA)
+}
+
+func (s *CheckersS) TestDurationBetween(c *check.C) {
+	testInfo(c, check.DurationBetween, "DurationBetween", []string{"obtained", "min", "max"})
+
+	testCheck(c, check.DurationBetween, true, "", time.Second, time.Second, 2*time.Second)
+	testCheck(c, check.DurationBetween, true, "", 2*time.Second, time.Second, 2*time.Second)
+	testCheck(c, check.DurationBetween, false, "Difference: 3s is not between 1s and 2s", 3*time.Second, time.Second, 2*time.Second)
+	testCheck(c, check.DurationBetween, false, "max value is not a time.Duration", time.Second, time.Second, 2)
+	testCheck(c, check.DurationBetween, false, "min must not be more than max", time.Second, 2*time.Second, time.Second)
+}
+
+func (s *CheckersS) TestCompareTimes(c *check.C) {
+	testCheck(c, check.MoreThan, true, "", timeB, timeA)
+	testCheck(c, check.MoreThan, false, "Difference: 2024-01-02T03:04:05Z <= 2024-01-02T03:04:06.5Z", timeA, timeB)
+	testCheck(c, check.LessThan, true, "", timeA, timeB)
+	testCheck(c, check.LessThan, false, "Difference: 2024-01-02T03:04:05Z >= 2024-01-02T03:04:05Z", timeA, timeA)
+	testCheck(c, check.MoreOrEqualThan, true, "", timeA, timeA.In(time.FixedZone("X", 3600)))
+	testCheck(c, check.LessOrEqualThan, true, "", timeA, timeA)
+	testCheck(c, check.LessOrEqualThan, false, "Difference: 2024-01-02T03:04:06.5Z > 2024-01-02T03:04:05Z", timeB, timeA)
+	testCheck(c, check.MoreThan, false, "Comparing incomparable type time.Time and int", timeA, 1)
+
+	testCheck(c, check.MoreThan, true, "", 2*time.Second, time.Second)
+	testCheck(c, check.LessThan, false, "Difference: 2s >= 1s", 2*time.Second, time.Second)
+}
@@ -0,0 +1,124 @@
+package check
+
+import (
+	"fmt"
+	"time"
+)
+
+// -----------------------------------------------------------------------
+// Polling of conditions met by asynchronous code.
+
+// Eventually evaluates condition every interval until it's met, for at
+// most timeout, and returns whether it was met.  The condition is either
+// a func() bool, or a func(c *C) which is met when the checks it does with
+// the given C pass.  That C is a scratch one, created anew for every
+// attempt, so failing checks don't fail the test by themselves, and
+// Assert merely ends the attempt, which isn't met when skipped either.
+// Functions registered with its Cleanup are run once the attempt is over.
+// When the condition isn't met in time the test is marked as failed, and
+// the output of the last failing attempt is logged.
+//
+// For example:
+//
+//	c.Eventually(func(c *C) {
+//		c.Assert(cache.Len(), Equals, 3)
+//	}, time.Second, 10*time.Millisecond)
+func (c *C) Eventually(condition any, timeout, interval time.Duration) bool {
+	return c.poll("Eventually", condition, timeout, interval, true)
+}
+
+// Consistently evaluates condition every interval for the given duration,
+// and returns whether it was met every time.  The condition is just like
+// for Eventually.  As soon as the condition isn't met the test is marked
+// as failed, and the output of the failing attempt is logged.
+//
+// For example:
+//
+//	c.Consistently(func() bool { return !closed.Load() }, 100*time.Millisecond, 10*time.Millisecond)
+func (c *C) Consistently(condition any, duration, interval time.Duration) bool {
+	return c.poll("Consistently", condition, duration, interval, false)
+}
+
+func (c *C) poll(funcName string, condition any, duration, interval time.Duration, eventually bool) bool {
+	var attempt func() (bool, string)
+	switch f := condition.(type) {
+	case func() bool:
+		attempt = func() (bool, string) { return f(), "" }
+	case func(c *C):
+		attempt = func() (bool, string) { return c.scratchAttempt(f) }
+	default:
+		panic(funcName + " needs a func() bool or func(*C) condition")
+	}
+	if interval <= 0 {
+		panic(funcName + " needs a positive interval")
+	}
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	for attempts := 1; ; attempts++ {
+		ok, log := attempt()
+		timeUp := !time.Now().Before(deadline)
+		if ok && (eventually || timeUp) {
+			return true
+		}
+		if !ok && (!eventually || timeUp) {
+			c.logCaller(2)
+			if eventually {
+				c.logString(fmt.Sprintf("Condition not met within %v (%s)", duration, pluralize(attempts, "attempt")))
+			} else {
+				c.logString(fmt.Sprintf("Condition not met after %v (%s)", time.Since(start), pluralize(attempts, "attempt")))
+			}
+			if log != "" {
+				c.writeLog([]byte(log))
+			} else {
+				c.logNewLine()
+			}
+			c.Fail()
+			return false
+		}
+		wait := interval
+		if remaining := time.Until(deadline); remaining < wait {
+			wait = remaining
+		}
+		time.Sleep(wait)
+	}
+}
+
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// scratchAttempt runs f with a scratch C, which stands for c, and returns
+// whether the attempt succeeded, rather than failing or being skipped,
+// along with its output.  Functions registered with Cleanup by f are run
+// once the attempt is over.
+func (c *C) scratchAttempt(f func(c *C)) (bool, string) {
+	scratch := &C{
+		method:   c.method,
+		kind:     c.kind,
+		testName: c.testName,
+		logb:     &logger{},
+		tempDir:  c.tempDir,
+		testingT: c.testingT,
+		deadline: c.deadline,
+		runner:   c.runner,
+		cleanups: new(cleanupStack),
+		filesDir: c.filesDir,
+	}
+	done := make(chan bool)
+	var panicked any
+	go func() {
+		defer close(done)
+		defer func() { panicked = recover() }()
+		defer scratch.cleanups.run()
+		f(scratch)
+	}()
+	<-done
+	if panicked != nil {
+		panic(panicked) // In the test's goroutine, where it's reported.
+	}
+	return scratch.status() == succeededSt, scratch.logb.String()
+}
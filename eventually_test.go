@@ -0,0 +1,120 @@
+package check_test
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/iostrovok/check"
+)
+
+func (s *HelpersS) TestEventually(c *check.C) {
+	var n int32
+	go func() {
+		for i := 0; i < 3; i++ {
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&n, 1)
+		}
+	}()
+	result := c.Eventually(func(c *check.C) {
+		c.Check(atomic.LoadInt32(&n), check.Equals, int32(3))
+	}, 5*time.Second, time.Millisecond)
+	checkState(c, result, &expectedState{name: "Eventually", result: true})
+
+	attempts := 0
+	result = c.Eventually(func() bool {
+		attempts++
+		return attempts == 3
+	}, 5*time.Second, time.Millisecond)
+	checkState(c, result, &expectedState{name: "Eventually", result: true})
+	c.Assert(attempts, check.Equals, 3)
+}
+
+func (s *HelpersS) TestEventuallyFail(c *check.C) {
+	attempts := 0
+	log := "(?s)eventually_test\\.go:[0-9]+:.*\neventually_test\\.go:[0-9]+:\n" +
+		"    return c\\.Eventually\\(func\\(c \\*check\\.C\\) {\n.*" +
+		"\\.\\.\\. Condition not met within 20ms \\([0-9]+ attempts\\)\n" +
+		"eventually_test\\.go:[0-9]+:\n" +
+		"    c\\.Assert\\(attempts, check\\.Equals, -1\\)\n" +
+		"\\.\\.\\. obtained int = [0-9]+\n" +
+		"\\.\\.\\. expected int = -1\n\n"
+	testHelperFailure(c, "Eventually(func(c), ...)", false, false, log,
+		func() any {
+			return c.Eventually(func(c *check.C) {
+				attempts++
+				c.Assert(attempts, check.Equals, -1)
+				c.Error("not reached")
+			}, 20*time.Millisecond, 5*time.Millisecond)
+		})
+	c.Assert(attempts > 1, check.Equals, true)
+}
+
+func (s *HelpersS) TestEventuallyFailBool(c *check.C) {
+	log := "(?s)eventually_test\\.go:[0-9]+:.*\neventually_test\\.go:[0-9]+:\n" +
+		"    return c\\.Eventually\\(func\\(\\) bool { return false }, 0, time\\.Millisecond\\)\n" +
+		"\\.\\.\\. Condition not met within 0s \\(1 attempt\\)\n\n"
+	testHelperFailure(c, "Eventually(func() bool, ...)", false, false, log,
+		func() any {
+			return c.Eventually(func() bool { return false }, 0, time.Millisecond)
+		})
+}
+
+func (s *HelpersS) TestEventuallyCleanup(c *check.C) {
+	attempts, cleanups := 0, 0
+	result := c.Eventually(func(c *check.C) {
+		attempts++
+		c.Cleanup(func() { cleanups++ })
+		c.Check(attempts, check.Equals, 2)
+	}, 5*time.Second, time.Millisecond)
+	checkState(c, result, &expectedState{name: "Eventually", result: true})
+	c.Assert(cleanups, check.Equals, 2)
+}
+
+func (s *HelpersS) TestEventuallySkip(c *check.C) {
+	log := "(?s)eventually_test\\.go:[0-9]+:.*\neventually_test\\.go:[0-9]+:\n" +
+		"    return c\\.Eventually\\(func\\(c \\*check\\.C\\) { c\\.Skip\\(\"not yet\"\\) }, 0, time\\.Millisecond\\)\n" +
+		"\\.\\.\\. Condition not met within 0s \\(1 attempt\\)\n\n"
+	testHelperFailure(c, "Eventually(func(c) { c.Skip(...) }, ...)", false, false, log,
+		func() any {
+			return c.Eventually(func(c *check.C) { c.Skip("not yet") }, 0, time.Millisecond)
+		})
+}
+
+func (s *HelpersS) TestConsistently(c *check.C) {
+	attempts := 0
+	result := c.Consistently(func(c *check.C) {
+		attempts++
+		c.Check(attempts, check.Not(check.Equals), 0)
+	}, 20*time.Millisecond, 5*time.Millisecond)
+	checkState(c, result, &expectedState{name: "Consistently", result: true})
+	c.Assert(attempts > 1, check.Equals, true)
+}
+
+func (s *HelpersS) TestConsistentlyFail(c *check.C) {
+	attempts := 0
+	log := "(?s)eventually_test\\.go:[0-9]+:.*\neventually_test\\.go:[0-9]+:\n" +
+		"    return c\\.Consistently\\(func\\(c \\*check\\.C\\) {\n.*" +
+		"\\.\\.\\. Condition not met after .+ \\(3 attempts\\)\n" +
+		"eventually_test\\.go:[0-9]+:\n" +
+		"    c\\.Check\\(attempts < 3, check\\.Equals, true\\)\n" +
+		"\\.\\.\\. obtained bool = false\n" +
+		"\\.\\.\\. expected bool = true\n\n"
+	testHelperFailure(c, "Consistently(func(c), ...)", false, false, log,
+		func() any {
+			return c.Consistently(func(c *check.C) {
+				attempts++
+				c.Check(attempts < 3, check.Equals, true)
+			}, 5*time.Second, time.Millisecond)
+		})
+	c.Assert(attempts, check.Equals, 3)
+}
+
+func (s *HelpersS) TestEventuallyPanics(c *check.C) {
+	c.Assert(func() { c.Eventually(func() {}, time.Second, time.Millisecond) },
+		check.PanicMatches, "Eventually needs a func\\(\\) bool or func\\(\\*C\\) condition")
+	c.Assert(func() { c.Consistently(func() bool { return true }, time.Second, 0) },
+		check.PanicMatches, "Consistently needs a positive interval")
+	c.Assert(func() {
+		c.Eventually(func(c *check.C) { panic("boom") }, time.Second, time.Millisecond)
+	}, check.PanicMatches, "boom")
+}
@@ -1,6 +1,9 @@
 package check
 
-import "io"
+import (
+	"io"
+	"time"
+)
 
 func PrintLine(filename string, line int) (string, error) {
 	return printLine(filename, line)
@@ -11,9 +14,23 @@ func Indent(s, with string) string {
 }
 
 func NewOutputWriter(writer io.Writer, stream, verbose bool) *outputWriter {
-	return newOutputWriter(writer, stream, verbose)
+	return newOutputWriter(writer, nil, stream, verbose)
 }
 
 func (c *C) FakeSkip(reason string) {
 	c.reason = reason
 }
+
+func SetLeakGracePeriod(d time.Duration) (restore func()) {
+	old := leakGracePeriod
+	leakGracePeriod = d
+	return func() { leakGracePeriod = old }
+}
+
+func FuncPackage(name string) string {
+	return funcPackage(name)
+}
+
+func RunSuites(suites []any, conf *RunConf) *CheckTestResult {
+	return runSuites(suites, conf)
+}
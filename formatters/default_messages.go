@@ -2,8 +2,87 @@ package formatters
 
 import (
 	"fmt"
+	"strings"
 )
 
+const separator = "\n-----------------------------------" +
+	"-----------------------------------\n"
+
 func DefaultOutput(prefix, label, funcPath, funcName, suffix string) string {
 	return fmt.Sprintf("%s%s: %s: %s%s", prefix, label, funcPath, funcName, suffix)
 }
+
+// -----------------------------------------------------------------------
+// The default formatter writes problems along with their log, and also
+// successes in verbose mode.  In stream mode the log is written as it goes
+// instead.
+
+type defaultFormatter struct {
+	wroteCallProblemLast bool
+}
+
+func (f *defaultFormatter) SuiteStarted(suite string) string {
+	return ""
+}
+
+func (f *defaultFormatter) CallStarted(d Data) string {
+	if !d.Stream {
+		return ""
+	}
+	return DefaultOutput("", d.Label, d.FuncPath, d.FuncName, "\n")
+}
+
+func (f *defaultFormatter) CallDone(d Data) string {
+	if d.Problem {
+		return f.callProblem(d)
+	}
+	return f.callSuccess(d)
+}
+
+func (f *defaultFormatter) callProblem(d Data) string {
+	var prefix string
+	if !d.Stream {
+		prefix = separator
+	}
+	f.wroteCallProblemLast = true
+	out := DefaultOutput(prefix, d.Label, d.FuncPath, d.FuncName, "\n\n")
+	if !d.Stream {
+		out += d.StdOut
+	}
+	return out
+}
+
+func (f *defaultFormatter) callSuccess(d Data) string {
+	if !d.Stream && !(d.Verbose && !d.Fixture) {
+		return ""
+	}
+	var suffix string
+	if d.Reason != "" {
+		suffix = " (" + d.Reason + ")"
+	}
+	if d.Timer != "" {
+		suffix += "\t" + d.Timer
+	}
+	suffix += "\n"
+	if d.Stream {
+		suffix += "\n"
+	}
+	var prefix string
+	if !d.Stream && f.wroteCallProblemLast {
+		prefix = separator
+	}
+	f.wroteCallProblemLast = false
+	return DefaultOutput(prefix, d.Label, d.FuncPath, d.FuncName, suffix)
+}
+
+func (f *defaultFormatter) Output(d Data, text string) string {
+	return text
+}
+
+func (f *defaultFormatter) RunDone(s Summary) string {
+	if len(s.ObsoleteSnapshots) == 0 {
+		return ""
+	}
+	return "Obsolete snapshots (run with -check.update-snapshots to remove them):\n    " +
+		strings.Join(s.ObsoleteSnapshots, "\n    ") + "\n"
+}
@@ -1,27 +1,98 @@
 package formatters
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
-type Formatter string
+// Formatter renders the events of a run into the text written to the run
+// output.  Any method may return an empty string when there's nothing to
+// write for the event.  Calls to a formatter are serialized, but events of
+// parallel tests may be interleaved.
+type Formatter interface {
+	// SuiteStarted is called before the methods of a suite are run.
+	SuiteStarted(suite string) string
+	// CallStarted is called when a test or fixture method starts.
+	CallStarted(d Data) string
+	// CallDone is called when a test or fixture method is over, with the
+	// outcome in d.Label (e.g. "PASS", "FAIL", "SKIP").
+	CallDone(d Data) string
+	// Output is called with every chunk of text logged by a test or fixture
+	// method while streaming.
+	Output(d Data, text string) string
+	// RunDone is called once all suites of the run are over.  Its text is
+	// written to RunConf.ReportOutput.
+	RunDone(s Summary) string
+}
 
 const (
 	DefaultFormatter  = "default"
 	JsonFormatter     = "json"
 	TeamcityFormatter = "teamcity"
+	JunitFormatter    = "junit"
 )
 
-func F(name *string) Formatter {
-	switch strings.ToLower(*name) {
-	case "json":
-		return JsonFormatter
-	case "teamcity":
-		return TeamcityFormatter
+var (
+	registryM sync.Mutex
+	registry  = map[string]func() Formatter{
+		DefaultFormatter:  func() Formatter { return &defaultFormatter{} },
+		JsonFormatter:     func() Formatter { return &jsonFormatter{} },
+		TeamcityFormatter: func() Formatter { return &teamcityFormatter{} },
+		JunitFormatter:    func() Formatter { return &junitFormatter{report: NewJUnitReport()} },
 	}
+)
 
-	return DefaultFormatter
+// Register makes a formatter available under the given name, so it may be
+// selected with the -check.format flag.  The factory is called once per
+// run, since formatters may keep state.  It's meant to be called from init.
+func Register(name string, factory func() Formatter) {
+	registryM.Lock()
+	defer registryM.Unlock()
+	registry[strings.ToLower(name)] = factory
+}
+
+// Lookup returns a new formatter registered under the given name.
+func Lookup(name string) (Formatter, bool) {
+	registryM.Lock()
+	factory, ok := registry[strings.ToLower(name)]
+	registryM.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// F returns a new formatter registered under the given name, or the
+// default one when the name is empty.
+func F(name *string) (Formatter, error) {
+	if *name == "" {
+		f, _ := Lookup(DefaultFormatter)
+		return f, nil
+	}
+	if f, ok := Lookup(*name); ok {
+		return f, nil
+	}
+
+	registryM.Lock()
+	known := make([]string, 0, len(registry))
+	for registered := range registry {
+		known = append(known, registered)
+	}
+	registryM.Unlock()
+	sort.Strings(known)
+	return nil, fmt.Errorf("unknown formatter %q, want one of: %s", *name, strings.Join(known, ", "))
+}
+
+// StreamFormatter may be implemented by formatters which decide whether
+// the run is streamed when they're selected with the -check.format flag.
+// Other formatters are streamed, since they render the run as it goes.
+type StreamFormatter interface {
+	Formatter
+	// Stream returns whether the events of the run are to be streamed.
+	Stream() bool
 }
 
 type Data struct {
@@ -29,32 +100,43 @@ type Data struct {
 	Duration  time.Duration
 
 	TestName string
+	Suite    string
+	Method   string
+	Fixture  bool
+	Problem  bool // The call failed, and its log deserves to be shown.
+	Reason   string
+	Timer    string // Timing of succeeded calls, as in "0.001s".
 	StdOut   string
 	FuncPath string
-	Package  string
+	Package  string // Import path of the suite's package.
+	Parent   string // Name of the go test running the suites.
 	FuncName string
-	Prefix   string
 	Label    string
-	Suffix   string
 
-	Formatter    Formatter
+	Stream  bool
+	Verbose bool
+
 	FormatPrefix string
 }
 
-func Render(d Data) string {
-	out := ""
-
-	switch d.Formatter {
-	case TeamcityFormatter:
-		out = DefaultOutput(d.Prefix, d.Label, d.FuncPath, d.FuncName, d.Suffix) +
-			TeamcityOutput(d.Label, d.TestName, d.StdOut, d.StartTime, d.Duration,
-				d.FuncPath, d.FuncName, d.FormatPrefix, d.Suffix) + "\n"
-	case JsonFormatter:
-		out = JsonOutput(d.Label, d.TestName, d.StdOut, d.StartTime, d.Duration,
-			d.FuncPath) + "\n"
-	default:
-		out = DefaultOutput(d.Prefix, d.Label, d.FuncPath, d.FuncName, d.Suffix)
-	}
+// Summary describes the outcome of a whole run.
+type Summary struct {
+	StartTime time.Time
+	Duration  time.Duration
+
+	Succeeded        int
+	Failed           int
+	Skipped          int
+	Panicked         int
+	FixturePanicked  int
+	ExpectedFailures int
+	Missed           int
+	TimedOut         int
+	Flaky            int
+
+	Passed bool
+	Text   string // As in "OOPS: 1 passed, 1 FAILED".
 
-	return out
+	// Snapshots not matched by tests anymore, as in "__snapshots__/S.snap: TestX 1".
+	ObsoleteSnapshots []string
 }
@@ -1,23 +1,21 @@
 /*
-	Convert go test output to TeamCity format
-	Support Run, Skip, Pass, Fail
+	Convert gocheck output to the go test2json format
+	Support Run, Output, Skip, Pass, Fail
 
 	For more details see:
-		- https://github.com/2tvenom/go-test-teamcity
-		- https://confluence.jetbrains.com/display/TCD9/Build+Script+Interaction+with+TeamCity
+		- https://pkg.go.dev/cmd/test2json
 */
 
 package formatters
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
 	"strings"
 	"time"
 )
 
-// -----------------------------------------------------------------------
-// Output writer manages atomic output writing according to settings.
-
 type JsonTestEventAction string
 
 // https://github.com/golang/go/blob/master/src/cmd/test2json/main.go
@@ -32,42 +30,132 @@ const (
 	JsonTestEventActionSkip   = "skip"   //  the test was skipped or the package contained no tests
 )
 
+// JsonTestEvent is encoded just as test2json does.
 type JsonTestEvent struct {
-	Time    string // encodes as an RFC3339-format string
+	Time    *time.Time `json:",omitempty"` // encodes as an RFC3339-format string
 	Action  JsonTestEventAction
-	Package string
-	Test    string
-	Elapsed float64 // seconds
-	Output  string
+	Package string   `json:",omitempty"`
+	Test    string   `json:",omitempty"`
+	Elapsed *float64 `json:",omitempty"` // seconds
+	Output  string   `json:",omitempty"`
 }
 
-// {"Time":"2022-05-26T13:59:39.562101-04:00","Action":"output","Package":"","Test":"TestService","Output":"OK: 1 passed\n"}
-func JsonOutput(status string, testName, stdOut string, startTime time.Time, testDuration time.Duration,
-	funcPath string) string {
-	out := JsonTestEvent{
-		Time:    startTime.Format(time.RFC3339),
-		Package: funcPath,
-		Action:  JsonTestEventActionOutput,
-		Test:    testName,
-		Elapsed: float64(testDuration) / float64(time.Second),
-		Output:  strings.TrimSpace(stdOut),
+// JsonAction returns the test2json action reporting a call label, and the
+// result as written by go test.
+func JsonAction(status string) (action JsonTestEventAction, result string) {
+	switch status {
+	case "PASS", "FAIL EXPECTED", "FLAKY":
+		return JsonTestEventActionPass, "PASS"
+	case "SKIP", "MISS", "RETRY":
+		// A failed attempt which is retried doesn't fail the run.
+		return JsonTestEventActionSkip, "SKIP"
+	default: // "FAIL", "PANIC", "TIMEOUT"
+		return JsonTestEventActionFail, "FAIL"
 	}
+}
 
-	switch status {
-	case "START":
-		out.Action = JsonTestEventActionRun
-	case "SKIP":
-		out.Action = JsonTestEventActionSkip
-	case "MISS":
-		out.Action = JsonTestEventActionCount
-	case "FAIL":
-		out.Action = JsonTestEventActionFail
-	case "PASS", "FAIL EXPECTED":
-		out.Action = JsonTestEventActionPass
-	default: // "PANIC"
-		out.Output = JsonTestEventActionFail
+// -----------------------------------------------------------------------
+// The json formatter writes one event per line, with every suite test
+// reported as a subtest of the go test running the suites.
+
+type jsonFormatter struct {
+	pkg     string
+	parent  string
+	started bool
+}
+
+func (f *jsonFormatter) event(action JsonTestEventAction, test, output string, elapsed *time.Duration) string {
+	now := time.Now()
+	e := JsonTestEvent{Time: &now, Action: action, Package: f.pkg, Test: test, Output: output}
+	if elapsed != nil {
+		// As parsed by test2json from the "--- PASS: Test (0.01s)" lines.
+		seconds := math.Round(elapsed.Seconds()*100) / 100
+		e.Elapsed = &seconds
+	}
+	b, _ := json.Marshal(e)
+	return string(b) + "\n"
+}
+
+// output returns an output event per line of text, with the indentation
+// go test uses for subtests.
+func (f *jsonFormatter) output(test, text, indent string) string {
+	var out strings.Builder
+	for text != "" {
+		line := text
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			line = text[:i+1]
+		}
+		text = text[len(line):]
+		out.WriteString(f.event(JsonTestEventActionOutput, test, indent+line, nil))
+	}
+	return out.String()
+}
+
+func (f *jsonFormatter) testName(d Data) string {
+	if d.TestName == "" {
+		// Suite fixtures belong to no test in particular.
+		return f.parent
+	}
+	return f.parent + "/" + d.TestName
+}
+
+func (f *jsonFormatter) SuiteStarted(suite string) string {
+	return ""
+}
+
+func (f *jsonFormatter) CallStarted(d Data) string {
+	var out string
+	if !f.started {
+		f.started = true
+		f.pkg, f.parent = d.Package, d.Parent
+		out += f.event(JsonTestEventActionRun, f.parent, "", nil)
+		out += f.event(JsonTestEventActionOutput, f.parent, "=== RUN   "+f.parent+"\n", nil)
+	}
+	if d.Fixture {
+		return out
+	}
+	name := f.testName(d)
+	out += f.event(JsonTestEventActionRun, name, "", nil)
+	out += f.event(JsonTestEventActionOutput, name, "=== RUN   "+name+"\n", nil)
+	return out
+}
+
+func (f *jsonFormatter) CallDone(d Data) string {
+	var out string
+	name := f.testName(d)
+	if !d.Stream && (d.Problem || !d.Fixture) {
+		// The log wasn't written as it went.
+		out += f.output(name, d.StdOut, "    ")
 	}
+	if d.Fixture {
+		if d.Problem {
+			out += f.output(name, DefaultOutput("", d.Label, d.FuncPath, d.FuncName, "\n"), "    ")
+		}
+		return out
+	}
+
+	action, result := JsonAction(d.Label)
+	out += f.output(name, fmt.Sprintf("--- %s: %s (%.2fs)\n", result, name, d.Duration.Seconds()), "    ")
+	if d.Reason != "" {
+		out += f.output(name, d.Reason+"\n", "        ")
+	}
+	return out + f.event(action, name, "", &d.Duration)
+}
 
-	b, _ := json.Marshal(out)
-	return string(b)
+func (f *jsonFormatter) Output(d Data, text string) string {
+	return f.output(f.testName(d), text, "    ")
+}
+
+func (f *jsonFormatter) RunDone(s Summary) string {
+	if !f.started {
+		return ""
+	}
+	action, result := JsonAction("PASS")
+	if !s.Passed {
+		action, result = JsonAction("FAIL")
+	}
+	out := f.output(f.parent, fmt.Sprintf("--- %s: %s (%.2fs)\n", result, f.parent, s.Duration.Seconds()), "")
+	out += f.event(action, f.parent, "", &s.Duration)
+	out += f.output("", result+"\n", "")
+	return out + f.event(action, "", "", &s.Duration)
 }
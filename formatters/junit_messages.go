@@ -0,0 +1,184 @@
+/*
+	Aggregate gocheck results into a JUnit XML report
+	Support Pass, Fail, Error (panics and timeouts), Skip
+
+	For more details see:
+		- https://llg.cubic.org/docs/junit/
+*/
+
+package formatters
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------
+// JUnit is a whole-document format, so calls are collected while the
+// suites run and the report is written once everything is done.
+
+type JUnitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*JUnitTestSuite `xml:"testsuite"`
+}
+
+type JUnitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Cases     []*JUnitTestCase `xml:"testcase"`
+
+	duration time.Duration
+}
+
+type JUnitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *JUnitProblem `xml:"failure,omitempty"`
+	Error     *JUnitProblem `xml:"error,omitempty"`
+	Skipped   *JUnitProblem `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type JUnitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// JUnitReport collects finished calls into a JUnit XML document.
+// It's safe for concurrent use.
+type JUnitReport struct {
+	m      sync.Mutex
+	suites []*JUnitTestSuite
+	index  map[string]*JUnitTestSuite
+}
+
+func NewJUnitReport() *JUnitReport {
+	return &JUnitReport{index: map[string]*JUnitTestSuite{}}
+}
+
+// Add records a finished call.  Fixture calls are only recorded when
+// they have problems, since they aren't test cases by themselves.
+func (r *JUnitReport) Add(d Data) {
+	tc := &JUnitTestCase{
+		Name:      d.Method,
+		Classname: d.Suite,
+		Time:      junitSeconds(d.Duration),
+		SystemOut: strings.TrimSpace(d.StdOut),
+	}
+
+	switch d.Label {
+	case "RETRY":
+		// Only the last attempt of a test is reported.
+		return
+	case "PASS", "FAIL EXPECTED", "FLAKY":
+	case "SKIP":
+		tc.Skipped = &JUnitProblem{Message: junitMessage(d.Reason, "Test is skipped")}
+	case "MISS":
+		tc.Skipped = &JUnitProblem{Message: "Test is missed"}
+	case "FAIL":
+		tc.Failure = &JUnitProblem{Message: "Test failed", Type: d.Label, Body: tc.SystemOut}
+	case "TIMEOUT":
+		tc.Error = &JUnitProblem{Message: "Test timed out", Type: d.Label, Body: tc.SystemOut}
+	default: // "PANIC"
+		tc.Error = &JUnitProblem{Message: "Test ended in panic", Type: d.Label, Body: tc.SystemOut}
+	}
+	if d.Fixture && tc.Failure == nil && tc.Error == nil {
+		return
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+	ts := r.index[d.Suite]
+	if ts == nil {
+		ts = &JUnitTestSuite{Name: d.Suite, Timestamp: d.StartTime.Format("2006-01-02T15:04:05")}
+		r.index[d.Suite] = ts
+		r.suites = append(r.suites, ts)
+	}
+	ts.Cases = append(ts.Cases, tc)
+	ts.Tests++
+	ts.duration += d.Duration
+	ts.Time = junitSeconds(ts.duration)
+	switch {
+	case tc.Failure != nil:
+		ts.Failures++
+	case tc.Error != nil:
+		ts.Errors++
+	case tc.Skipped != nil:
+		ts.Skipped++
+	}
+}
+
+// WriteTo writes the whole report as a single XML document.
+func (r *JUnitReport) WriteTo(w io.Writer) (int64, error) {
+	r.m.Lock()
+	doc := JUnitTestSuites{Suites: r.suites}
+	var duration time.Duration
+	for _, ts := range r.suites {
+		doc.Tests += ts.Tests
+		doc.Failures += ts.Failures
+		doc.Errors += ts.Errors
+		doc.Skipped += ts.Skipped
+		duration += ts.duration
+	}
+	doc.Time = junitSeconds(duration)
+	out, err := xml.MarshalIndent(doc, "", "\t")
+	r.m.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := fmt.Fprintf(w, "%s%s\n", xml.Header, out)
+	return int64(n), err
+}
+
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func junitMessage(message, alternative string) string {
+	if message == "" {
+		return alternative
+	}
+	return message
+}
+
+// -----------------------------------------------------------------------
+// The junit formatter writes the default output as it goes, and the
+// report once the run is over.
+
+type junitFormatter struct {
+	defaultFormatter
+	report *JUnitReport
+}
+
+// Stream returns false, since the report is written as a whole.
+func (f *junitFormatter) Stream() bool {
+	return false
+}
+
+func (f *junitFormatter) CallDone(d Data) string {
+	f.report.Add(d)
+	return f.defaultFormatter.CallDone(d)
+}
+
+func (f *junitFormatter) RunDone(s Summary) string {
+	var b strings.Builder
+	f.report.WriteTo(&b)
+	return b.String()
+}
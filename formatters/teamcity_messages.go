@@ -61,10 +61,15 @@ func TeamcityOutput(status string, testName, stdOut string, startTime time.Time,
 		out += fmt.Sprintf("##teamcity[testIgnored timestamp='%s' name='%s' message='%s']\n", now, testName, "Test is skipped")
 	case "MISS":
 		out += fmt.Sprintf("##teamcity[testIgnored timestamp='%s' name='%s' message='%s']\n", now, testName, "Test is missed")
+	case "RETRY":
+		out += fmt.Sprintf("##teamcity[testIgnored timestamp='%s' name='%s' message='%s']\n", now, testName, "Test failed and is retried")
 	case "FAIL":
 		out += fmt.Sprintf("##teamcity[testFailed timestamp='%s' name='%s' details='%s']\n",
 			now, testName, escapeLines(details))
-	case "PASS", "FAIL EXPECTED":
+	case "TIMEOUT":
+		out += fmt.Sprintf("##teamcity[testFailed timestamp='%s' name='%s' message='Test timed out.' details='%s']\n",
+			now, testName, escapeLines(details))
+	case "PASS", "FAIL EXPECTED", "FLAKY":
 		// ignore success cases
 	default: // "PANIC"
 		out += fmt.Sprintf("##teamcity[testFailed timestamp='%s' name='%s' message='Test ended in panic.' details='%s']\n",
@@ -76,3 +81,22 @@ func TeamcityOutput(status string, testName, stdOut string, startTime time.Time,
 
 	return out
 }
+
+// -----------------------------------------------------------------------
+// The teamcity formatter writes service messages after the default output.
+
+type teamcityFormatter struct {
+	defaultFormatter
+}
+
+func (f *teamcityFormatter) CallStarted(d Data) string {
+	return f.defaultFormatter.CallStarted(d) + f.message(d) + "\n"
+}
+
+func (f *teamcityFormatter) CallDone(d Data) string {
+	return f.defaultFormatter.CallDone(d) + f.message(d) + "\n"
+}
+
+func (f *teamcityFormatter) message(d Data) string {
+	return TeamcityOutput(d.Label, d.TestName, d.StdOut, d.StartTime, d.Duration, d.FormatPrefix, d.FuncPath, d.FuncName)
+}
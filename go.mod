@@ -2,4 +2,7 @@ module github.com/iostrovok/check
 
 go 1.22
 
-require github.com/iostrovok/go-convert v0.1.13
+require (
+	github.com/iostrovok/go-convert v0.1.13
+	gopkg.in/yaml.v3 v3.0.1
+)
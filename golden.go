@@ -0,0 +1,147 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iostrovok/check/pretty"
+)
+
+// -----------------------------------------------------------------------
+// Golden files.
+
+// A GoldenNormalizer rewrites the text compared with a golden file, so that
+// differences which don't matter are left out.  It's applied to both the
+// obtained text and the content of the golden file.
+type GoldenNormalizer func(text string) (string, error)
+
+// TrimTrailingSpace is a GoldenNormalizer removing the white space at the
+// end of every line.
+func TrimTrailingSpace(text string) (string, error) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t\r")
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// CanonicalJSON is a GoldenNormalizer re-encoding JSON text with sorted
+// object keys and a two spaces indentation.
+func CanonicalJSON(text string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return "", fmt.Errorf("invalid JSON: %v", err)
+	}
+	var out bytes.Buffer
+	encoder := json.NewEncoder(&out)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+type goldenChecker struct {
+	*CheckerInfo
+	normalizers []GoldenNormalizer
+}
+
+// The Golden checker verifies that the obtained string or []byte matches
+// the content of the given golden file.  When the tests are run with the
+// -check.update flag, the golden file is written with the obtained value
+// instead.
+//
+// For example:
+//
+//	c.Assert(rendered, Golden, "testdata/page.golden")
+var Golden Checker = GoldenWith()
+
+// GoldenWith returns a Golden checker applying the given normalizers
+// before the comparison.
+//
+// For example:
+//
+//	c.Assert(body, GoldenWith(CanonicalJSON), "testdata/reply.golden")
+func GoldenWith(normalizers ...GoldenNormalizer) Checker {
+	return &goldenChecker{
+		&CheckerInfo{Name: "Golden", Params: []string{"obtained", "golden file"}},
+		normalizers,
+	}
+}
+
+func (checker *goldenChecker) normalize(text string) (string, error) {
+	for _, normalizer := range checker.normalizers {
+		var err error
+		if text, err = normalizer(text); err != nil {
+			return "", err
+		}
+	}
+	return text, nil
+}
+
+func (checker *goldenChecker) Check(params []any, _ []string) (result bool, error string) {
+	var obtained string
+	switch value := params[0].(type) {
+	case string:
+		obtained = value
+	case []byte:
+		obtained = string(value)
+	default:
+		return false, "obtained value must be a string or []byte"
+	}
+	path, ok := params[1].(string)
+	if !ok {
+		return false, "golden file must be a string"
+	}
+
+	obtained, err := checker.normalize(obtained)
+	if err != nil {
+		return false, "Cannot normalize the obtained value: " + err.Error()
+	}
+
+	if *newUpdateFlag {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return false, err.Error()
+		}
+		if err := os.WriteFile(path, []byte(obtained), 0644); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, "Golden file is missing, run with -check.update to create it"
+	} else if err != nil {
+		return false, err.Error()
+	}
+	golden, err := checker.normalize(string(data))
+	if err != nil {
+		return false, "Cannot normalize the golden file: " + err.Error()
+	}
+	if obtained == golden {
+		return true, ""
+	}
+
+	diff := pretty.LineDiff(strings.Split(golden, "\n"), strings.Split(obtained, "\n"))
+	return false, fmt.Sprintf(`Golden file difference (-golden +obtained, run with -check.update to accept it):
+%s`, formatMultiLine(strings.Join(diff, "\n"), false))
+}
+
+// Golden checks that obtained matches the golden file of the given name,
+// stopping the test otherwise, just like the Golden checker does.  The file
+// of the test "Suite.TestX" is testdata/Suite/TestX/<name>.golden, relative
+// to the directory of the package being tested.
+func (c *C) Golden(name string, obtained any, normalizers ...GoldenNormalizer) bool {
+	path := filepath.Join("testdata", c.method.suiteName(), c.method.Info.Name+c.subtestPath(), name+".golden")
+	return c.runObtainedExpectedAlias("Golden", obtained, filepath.ToSlash(path), GoldenWith(normalizers...), nil)
+}
@@ -0,0 +1,103 @@
+package check_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/iostrovok/check"
+)
+
+type GoldenS struct{}
+
+var _ = check.Suite(&GoldenS{})
+
+func setUpdateFlag(c *check.C, update bool) {
+	old := flag.Lookup("check.update").Value.String()
+	c.Assert(flag.Set("check.update", strconv.FormatBool(update)), check.IsNil)
+	c.Cleanup(func() { flag.Set("check.update", old) })
+}
+
+func (s *GoldenS) TestGoldenChecker(c *check.C) {
+	testInfo(c, check.Golden, "Golden", []string{"obtained", "golden file"})
+
+	path := filepath.Join(c.WriteFiles(map[string]string{"a.golden": "one\ntwo\n"}), "a.golden")
+	testCheck(c, check.Golden, true, "", "one\ntwo\n", path)
+	testCheck(c, check.Golden, true, "", []byte("one\ntwo\n"), path)
+	testCheck(c, check.Golden, false, "Golden file difference (-golden +obtained, run with -check.update to accept it):\n"+
+		"...     -[1]: \"two\"\n"+
+		"...     +[1]: \"three\"\n", "one\nthree\n", path)
+	testCheck(c, check.Golden, false, "Golden file difference (-golden +obtained, run with -check.update to accept it):\n"+
+		"...     +[0]: \"zero\"\n", "zero\none\ntwo\n", path)
+	testCheck(c, check.Golden, false, "Golden file is missing, run with -check.update to create it",
+		"one\n", filepath.Join(filepath.Dir(path), "missing.golden"))
+	testCheck(c, check.Golden, false, "obtained value must be a string or []byte", 42, path)
+}
+
+func (s *GoldenS) TestGoldenUpdate(c *check.C) {
+	setUpdateFlag(c, true)
+	path := filepath.Join(c.TempDir(), "new", "a.golden")
+	testCheck(c, check.Golden, true, "", "one\n", path)
+	data, err := os.ReadFile(path)
+	c.Assert(err, check.IsNil)
+	c.Assert(string(data), check.Equals, "one\n")
+}
+
+func (s *GoldenS) TestGoldenNormalizers(c *check.C) {
+	text, err := check.TrimTrailingSpace("a  \nb\t\n")
+	c.Assert(err, check.IsNil)
+	c.Assert(text, check.Equals, "a\nb\n")
+
+	text, err = check.CanonicalJSON(`{"b": [1, 2.50], "a": "<x>"}`)
+	c.Assert(err, check.IsNil)
+	c.Assert(text, check.Equals, "{\n  \"a\": \"<x>\",\n  \"b\": [\n    1,\n    2.50\n  ]\n}\n")
+
+	_, err = check.CanonicalJSON(`{"a":`)
+	c.Assert(err, check.ErrorMatches, "invalid JSON: .*")
+
+	path := filepath.Join(c.WriteFiles(map[string]string{"a.golden": "{\"a\": 1,\n \"b\": 2}  \n"}), "a.golden")
+	testCheck(c, check.GoldenWith(check.CanonicalJSON), true, "", `{"b":2,"a":1}`, path)
+	testCheckNoLine(c, check.GoldenWith(check.TrimTrailingSpace), false, "", `{"b":2,"a":1}`, path)
+}
+
+type GoldenHelper struct {
+	page string
+}
+
+func (s *GoldenHelper) TestRender(c *check.C) {
+	c.Golden("page", s.page, check.TrimTrailingSpace)
+	c.Run("sub", func(c *check.C) {
+		c.Golden("page", []byte(s.page))
+	})
+}
+
+func (s *GoldenS) TestCGolden(c *check.C) {
+	c.Chdir(c.TempDir())
+	helper := &GoldenHelper{page: "title  \nbody\n"}
+
+	output := String{}
+	result := check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 1 FAILED")
+	c.Check(output.value, check.Matches, `(?s).*\.\.\. golden file string = "testdata/GoldenHelper/TestRender/page.golden"\n`+
+		`\.\.\. Golden file is missing, run with -check.update to create it\n.*`)
+
+	setUpdateFlag(c, true)
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OK: 1 passed")
+	data, err := os.ReadFile("testdata/GoldenHelper/TestRender/page.golden")
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, "title\nbody\n")
+	data, err = os.ReadFile("testdata/GoldenHelper/TestRender/sub/page.golden")
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, "title  \nbody\n")
+
+	setUpdateFlag(c, false)
+	helper.page = "title\nbody changed\n"
+	output = String{}
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 1 FAILED")
+	c.Check(output.value, check.Matches, `(?s).*\.\.\. Golden file difference \(-golden \+obtained, run with -check.update to accept it\):\n`+
+		`\.\.\.     -\[1\]: "body"\n`+
+		`\.\.\.     \+\[1\]: "body changed"\n.*`)
+}
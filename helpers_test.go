@@ -4,10 +4,14 @@
 package check_test
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/iostrovok/check"
 )
@@ -119,6 +123,35 @@ func (s *HelpersS) TestCheckFailWithExpectedAndComment(c *check.C) {
 		})
 }
 
+func (s *HelpersS) TestAliasFailWithComment(c *check.C) {
+	log := "(?s)helpers_test\\.go:[0-9]+:.*\nhelpers_test\\.go:[0-9]+:\n" +
+		"    return c\\.Equals\\(1, 2, \"my %s\", \"comment\"\\)\n" +
+		"\\.+ obtained int = 1\n" +
+		"\\.+ expected int = 2\n" +
+		"\\.+ my comment\n\n"
+	testHelperFailure(c, "Equals(1, 2, msg)", nil, true, log,
+		func() any {
+			return c.Equals(1, 2, "my %s", "comment")
+		})
+}
+
+func (s *HelpersS) TestDeepEqualsWithAliasFailWithComment(c *check.C) {
+	type item struct {
+		ID   int
+		Name string
+	}
+	log := "(?s)helpers_test\\.go:[0-9]+:.*\nhelpers_test\\.go:[0-9]+:\n" +
+		"    return c\\.DeepEqualsWith\\(.*\\)\n" +
+		"\\.+ obtained check_test\\.item = .*\n" +
+		"\\.+ my comment\n" +
+		"\\.+ Difference:\n" +
+		"\\.+     Name: \"a\" != \"b\"\n\n\n"
+	testHelperFailure(c, "DeepEqualsWith(obtained, expected, msg)", nil, true, log,
+		func() any {
+			return c.DeepEqualsWith(item{1, "a"}, item{2, "b"}, "my comment", check.IgnoreFields("ID"))
+		})
+}
+
 func (s *HelpersS) TestCheckFailWithExpectedAndStaticComment(c *check.C) {
 	checker := &MyChecker{result: false}
 	log := "(?s)helpers_test\\.go:[0-9]+:.*\nhelpers_test\\.go:[0-9]+:\n" +
@@ -424,6 +457,320 @@ func (s *HelpersS) TestMkDir(c *check.C) {
 	c.Check(isDir(helper.path2), check.Equals, false)
 }
 
+// -----------------------------------------------------------------------
+// TempDir(), TempFile() and WriteFiles() tests.
+
+type TempDirHelper struct {
+	suiteDir string
+	testDir  string
+	file     string
+	files    string
+	existed  []bool
+}
+
+func (s *TempDirHelper) SetUpSuite(c *check.C) {
+	s.suiteDir = c.TempDir()
+}
+
+func (s *TempDirHelper) TearDownSuite(c *check.C) {
+	s.existed = append(s.existed, isDir(s.suiteDir), isDir(s.testDir))
+}
+
+func (s *TempDirHelper) TestDir(c *check.C) {
+	s.testDir = c.TempDir()
+	s.file = c.TempFile("data-*.txt", "contents")
+	s.files = c.WriteFiles(map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "b",
+	})
+	s.existed = append(s.existed, isDir(s.testDir), isDir(filepath.Dir(s.file)), isDir(s.files))
+}
+
+func (s *HelpersS) TestTempDir(c *check.C) {
+	helper := TempDirHelper{}
+	output := String{}
+	result := check.Run(&helper, &check.RunConf{Output: &output})
+	c.Assert(result.String(), check.Equals, "OK: 1 passed")
+	c.Check(filepath.Base(helper.suiteDir), check.Matches, "TempDirHelper\\.SetUpSuite-[0-9]+")
+	c.Check(filepath.Base(helper.testDir), check.Matches, "TempDirHelper\\.TestDir-[0-9]+")
+	c.Check(filepath.Base(helper.file), check.Matches, "data-[0-9]+\\.txt")
+	c.Check(helper.existed, check.DeepEquals, []bool{true, true, true, true, false})
+	c.Check(isDir(helper.suiteDir), check.Equals, false)
+}
+
+func (s *HelpersS) TestTempFile(c *check.C) {
+	path := c.TempFile("file-*", "contents")
+	data, err := os.ReadFile(path)
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, "contents")
+	c.Check(filepath.Dir(c.TempFile("file-*", "")), check.Equals, filepath.Dir(path))
+}
+
+func (s *HelpersS) TestWriteFiles(c *check.C) {
+	dir := c.WriteFiles(map[string]string{
+		"a.txt":      "a",
+		"sub/deep/b": "b",
+		"sub/c.json": "{}",
+	})
+	for name, contents := range map[string]string{"a.txt": "a", "sub/deep/b": "b", "sub/c.json": "{}"} {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		c.Assert(err, check.IsNil)
+		c.Check(string(data), check.Equals, contents)
+	}
+}
+
+type WriteFilesOutsideHelper struct{}
+
+func (s *WriteFilesOutsideHelper) Test(c *check.C) {
+	c.WriteFiles(map[string]string{"../escape": "x"})
+}
+
+func (s *HelpersS) TestWriteFilesOutside(c *check.C) {
+	output := String{}
+	result := check.Run(&WriteFilesOutsideHelper{}, &check.RunConf{Output: &output})
+	c.Check(result.Failed, check.Equals, 1)
+	c.Check(output.value, check.Matches, `(?s).*Cannot write file "../escape" outside of the temporary directory.*`)
+}
+
+// -----------------------------------------------------------------------
+// Cleanup() tests.
+
+type CleanupHelper struct {
+	calls []string
+	dir   string
+}
+
+func (s *CleanupHelper) trace(name string) {
+	s.calls = append(s.calls, name)
+}
+
+func (s *CleanupHelper) SetUpSuite(c *check.C) {
+	s.trace("SetUpSuite")
+	c.Cleanup(func() { s.trace("SetUpSuite cleanup") })
+}
+
+func (s *CleanupHelper) TearDownSuite(c *check.C) {
+	s.trace("TearDownSuite")
+}
+
+func (s *CleanupHelper) SetUpTest(c *check.C) {
+	s.trace("SetUpTest")
+	c.Cleanup(func() { s.trace("SetUpTest cleanup") })
+}
+
+func (s *CleanupHelper) TearDownTest(c *check.C) {
+	s.trace("TearDownTest " + os.Getenv("CHECK_CLEANUP_TEST"))
+}
+
+func (s *CleanupHelper) TestA(c *check.C) {
+	s.trace("TestA")
+	c.Cleanup(func() { s.trace("TestA cleanup 1") })
+	c.Cleanup(func() { s.trace("TestA cleanup 2") })
+	c.Setenv("CHECK_CLEANUP_TEST", "set")
+	s.dir = c.MkDir()
+	c.Chdir(s.dir)
+	wd, _ := os.Getwd()
+	s.trace("TestA " + os.Getenv("CHECK_CLEANUP_TEST") + " " + fmt.Sprint(wd == s.dir))
+	c.Assert(true, check.Equals, false)
+}
+
+func (s *CleanupHelper) TestB(c *check.C) {
+	s.trace("TestB")
+	c.Cleanup(func() { s.trace("TestB cleanup 1") })
+	c.Cleanup(func() {
+		s.trace("TestB cleanup 2")
+		panic("Cleanup panic!")
+	})
+}
+
+func (s *HelpersS) TestCleanup(c *check.C) {
+	wd, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	os.Unsetenv("CHECK_CLEANUP_TEST")
+
+	helper := CleanupHelper{}
+	output := String{}
+	result := check.Run(&helper, &check.RunConf{Output: &output})
+	c.Check(helper.calls, check.DeepEquals, []string{
+		"SetUpSuite",
+		"SetUpTest",
+		"TestA",
+		"TestA set true",
+		"TestA cleanup 2",
+		"TestA cleanup 1",
+		"TearDownTest ",
+		"SetUpTest cleanup",
+		"SetUpTest",
+		"TestB",
+		"TestB cleanup 2",
+		"TestB cleanup 1",
+		"TearDownTest ",
+		"SetUpTest cleanup",
+		"TearDownSuite",
+		"SetUpSuite cleanup",
+	})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 1 FAILED, 1 PANICKED")
+	c.Check(output.value, check.Matches, "(?s).*PANIC: .*CleanupHelper\\.TestB\n\n.*Cleanup panic!.*")
+
+	now, err := os.Getwd()
+	c.Assert(err, check.IsNil)
+	c.Check(now, check.Equals, wd)
+	_, ok := os.LookupEnv("CHECK_CLEANUP_TEST")
+	c.Check(ok, check.Equals, false)
+}
+
+type SuiteCleanupHelper struct {
+	calls []string
+	panic bool
+}
+
+func (s *SuiteCleanupHelper) SetUpSuite(c *check.C) {
+	c.Cleanup(func() {
+		s.calls = append(s.calls, "SetUpSuite cleanup")
+		if s.panic {
+			panic("Cleanup panic!")
+		}
+	})
+}
+
+func (s *SuiteCleanupHelper) Test(c *check.C) {
+	s.calls = append(s.calls, "Test")
+}
+
+func (s *HelpersS) TestCleanupWithoutTearDownSuite(c *check.C) {
+	helper := SuiteCleanupHelper{}
+	output := String{}
+	result := check.Run(&helper, &check.RunConf{Output: &output})
+	c.Check(helper.calls, check.DeepEquals, []string{"Test", "SetUpSuite cleanup"})
+	c.Check(result.String(), check.Equals, "OK: 1 passed")
+	c.Check(output.value, check.Equals, "")
+
+	// SetUpSuite is reported once, since its cleanups passed.
+	output = String{}
+	check.Run(&SuiteCleanupHelper{}, &check.RunConf{Output: &output, Stream: true})
+	c.Check(output.value, check.Matches, ""+
+		"START: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\n"+
+		"PASS: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\t *[.0-9]+s\n\n"+
+		"START: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.Test\n"+
+		"PASS: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.Test\t *[.0-9]+s\n\n")
+
+	// Unless they panicked.
+	output = String{}
+	result = check.Run(&SuiteCleanupHelper{panic: true}, &check.RunConf{Output: &output, Stream: true})
+	c.Check(result.String(), check.Equals, "OOPS: 1 passed, 1 FIXTURE-PANICKED")
+	c.Check(output.value, check.Matches, "(?s)"+
+		"START: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\n"+
+		"PASS: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\t *[.0-9]+s\n\n"+
+		"START: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.Test\n"+
+		"PASS: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.Test\t *[.0-9]+s\n\n"+
+		"START: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\n"+
+		"\\.\\.\\. Panic: Cleanup panic! .*"+
+		"PANIC: helpers_test\\.go:[0-9]+: SuiteCleanupHelper\\.SetUpSuite\n\n")
+}
+
+type ContextHelper struct {
+	suiteCtx   context.Context
+	calls      []string
+	deadline   bool
+	timeoutErr error
+	timeoutCtx context.Context
+}
+
+func (s *ContextHelper) trace(name string, ctx context.Context) {
+	s.calls = append(s.calls, fmt.Sprintf("%s %v", name, ctx.Err()))
+}
+
+func (s *ContextHelper) SetUpSuite(c *check.C) {
+	s.suiteCtx = c.Context()
+}
+
+func (s *ContextHelper) TearDownSuite(c *check.C) {
+	s.trace("TearDownSuite", s.suiteCtx)
+}
+
+func (s *ContextHelper) TestA(c *check.C) {
+	ctx := c.Context()
+	c.Check(c.Context(), check.Equals, ctx)
+	c.Cleanup(func() { s.trace("TestA cleanup", ctx) })
+	s.trace("TestA", ctx)
+	s.trace("TestA suite", s.suiteCtx)
+	deadline, ok := c.Deadline()
+	ctxDeadline, ctxOk := ctx.Deadline()
+	s.deadline = ok && ctxOk && ctxDeadline.Equal(deadline)
+	c.FailNow()
+}
+
+func (s *ContextHelper) TestB(c *check.C) {
+	s.timeoutCtx = c.Context()
+	<-s.timeoutCtx.Done()
+	s.timeoutErr = context.Cause(s.timeoutCtx)
+}
+
+func (s *HelpersS) TestContext(c *check.C) {
+	helper := ContextHelper{}
+	output := String{}
+	result := check.Run(&helper, &check.RunConf{Output: &output, TestTimeout: 50 * time.Millisecond})
+	c.Check(helper.calls, check.DeepEquals, []string{
+		"TestA <nil>",
+		"TestA suite <nil>",
+		"TestA cleanup context canceled",
+		"TearDownSuite <nil>",
+	})
+	c.Check(helper.suiteCtx.Err(), check.Equals, context.Canceled)
+	c.Check(helper.deadline, check.Equals, true)
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 1 FAILED, 1 TIMEOUT")
+
+	// The timed out test may only wake up once the run is over.
+	<-helper.timeoutCtx.Done()
+	c.Check(helper.timeoutCtx.Err(), check.Equals, context.DeadlineExceeded)
+	c.Check(context.Cause(helper.timeoutCtx), check.Equals, context.DeadlineExceeded)
+}
+
+type ParallelDeadlineHelper struct {
+	before, after, deadline time.Time
+}
+
+func (s *ParallelDeadlineHelper) TestParallel(c *check.C) {
+	ctx := c.Context()
+	s.before, _ = ctx.Deadline()
+	c.Parallel()
+	s.after, _ = ctx.Deadline()
+	s.deadline, _ = c.Deadline()
+}
+
+func (s *ParallelDeadlineHelper) TestSerial(c *check.C) {
+	time.Sleep(20 * time.Millisecond)
+}
+
+func (s *HelpersS) TestContextDeadlineParallel(c *check.C) {
+	helper := ParallelDeadlineHelper{}
+	output := String{}
+	result := check.Run(&helper, &check.RunConf{Output: &output, TestTimeout: time.Minute})
+	c.Check(result.String(), check.Equals, "OK: 2 passed")
+	// The deadline moves along with the one of the test while it's paused.
+	c.Check(helper.after.Sub(helper.before) >= 20*time.Millisecond, check.Equals, true)
+	c.Check(helper.after, check.Equals, helper.deadline)
+}
+
+type DeadlineHelper struct {
+	deadline time.Time
+	ok       bool
+}
+
+func (s *DeadlineHelper) Test(c *check.C) {
+	s.deadline, s.ok = c.Deadline()
+}
+
+func (s *HelpersS) TestDeadline(c *check.C) {
+	helper := DeadlineHelper{}
+	output := String{}
+	start := time.Now()
+	check.Run(&helper, &check.RunConf{Output: &output, TestTimeout: time.Minute})
+	c.Check(helper.ok, check.Equals, true)
+	c.Check(helper.deadline.After(start.Add(time.Minute)), check.Equals, true)
+	c.Check(helper.deadline.Before(time.Now().Add(time.Minute)), check.Equals, true)
+}
+
 func isDir(path string) bool {
 	if stat, err := os.Stat(path); err == nil {
 		return stat.IsDir()
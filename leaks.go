@@ -0,0 +1,146 @@
+package check
+
+import (
+	"runtime"
+	"strings"
+	"time"
+)
+
+// -----------------------------------------------------------------------
+// Detection of goroutines leaked by tests and suites.
+
+// LeakSuite may be implemented by suites which decide on their own whether
+// their tests are checked for leaked goroutines, whatever the value of
+// RunConf.DetectLeaks.
+type LeakSuite interface {
+	// DetectLeaks returns whether the suite is checked for leaks.
+	DetectLeaks() bool
+	// IgnoreLeaks returns the functions of goroutines which aren't leaks,
+	// in addition to the ones in RunConf.IgnoreLeaks.
+	IgnoreLeaks() []string
+}
+
+// Goroutines started lazily by the runtime and the standard library, which
+// are bound to live for as long as the process.
+var defaultIgnoredLeaks = []string{
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+}
+
+// How long goroutines started by a test are given to finish once the test
+// is over, before they're reported as leaked.
+var leakGracePeriod = time.Second
+
+// goroutine is a parsed entry of a runtime.Stack dump.
+type goroutine struct {
+	id    string
+	stack string
+}
+
+// matches returns whether any function in the stack of g starts with one
+// of the given prefixes.
+func (g goroutine) matches(prefixes []string) bool {
+	for _, line := range strings.Split(g.stack, "\n")[1:] {
+		if strings.HasPrefix(line, "\t") {
+			continue // File and line of the function above.
+		}
+		line = strings.TrimPrefix(line, "created by ")
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(line, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// goroutines returns all the goroutines currently running, starting with
+// the one calling it.
+func goroutines() []goroutine {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	stacks := strings.Split(strings.TrimSpace(string(buf)), "\n\n")
+	var result []goroutine
+	for _, stack := range stacks {
+		// As in "goroutine 7 [chan receive]:".
+		fields := strings.Fields(stack)
+		if len(fields) < 2 || fields[0] != "goroutine" {
+			continue
+		}
+		result = append(result, goroutine{id: fields[1], stack: stack})
+	}
+	return result
+}
+
+// leakSnapshot records the goroutines running before a test or a suite,
+// so the ones they leave behind may be told apart.
+type leakSnapshot struct {
+	ignore []string
+	before map[string]bool
+	parent *leakSnapshot // Of the suite, when it's the snapshot of a test.
+}
+
+// snapshotLeaks returns a snapshot of the running goroutines, unless leaks
+// aren't being detected.
+func (runner *suiteRunner) snapshotLeaks() *leakSnapshot {
+	if !runner.detectLeaks {
+		return nil
+	}
+	s := &leakSnapshot{ignore: runner.ignoredLeaks, before: make(map[string]bool), parent: runner.suiteLeaks}
+	for _, g := range goroutines() {
+		s.before[g.id] = true
+	}
+	return s
+}
+
+// leaked returns the goroutines started since the snapshot which are still
+// running, after giving them the grace period to finish.
+func (s *leakSnapshot) leaked() []goroutine {
+	deadline := time.Now().Add(leakGracePeriod)
+	delay := time.Millisecond
+	for {
+		var leaked []goroutine
+		for _, g := range goroutines()[1:] { // Not the one checking.
+			if !s.before[g.id] && !g.matches(s.ignore) {
+				leaked = append(leaked, g)
+			}
+		}
+		if len(leaked) == 0 || time.Now().After(deadline) {
+			return leaked
+		}
+		time.Sleep(delay)
+		if delay < 100*time.Millisecond {
+			delay *= 2
+		}
+	}
+}
+
+// checkLeaks fails c with the stacks of the goroutines leaked since the
+// snapshot was taken.
+func (c *C) checkLeaks(s *leakSnapshot) {
+	if s == nil {
+		return
+	}
+	leaked := s.leaked()
+	if len(leaked) == 0 {
+		return
+	}
+	stacks := make([]string, len(leaked))
+	for i, g := range leaked {
+		stacks[i] = g.stack
+		if s.parent != nil {
+			// Reported once, rather than again by the suite.
+			s.parent.before[g.id] = true
+		}
+	}
+	c.logf("... Leaked goroutines:\n\n%s\n", strings.Join(stacks, "\n\n"))
+	c.Fail()
+}
@@ -49,6 +49,36 @@ func Pdiff(p Printfer, a, b interface{}) {
 	d.diff(reflect.ValueOf(a), reflect.ValueOf(b))
 }
 
+// DiffOptions customize the comparison done by DiffWith.
+type DiffOptions struct {
+	// IgnoreField reports whether the struct field found at path, as in
+	// "Items[0].ID", is left out of the comparison.
+	IgnoreField func(path string, field reflect.StructField) bool
+
+	// EquateEmpty makes nil and empty slices or maps equal.  They're
+	// different otherwise, unlike with Diff.
+	EquateEmpty bool
+
+	// Transform, if set, is applied to both values before they're
+	// compared, such as to sort slices.  It must keep their type.
+	Transform func(v reflect.Value) reflect.Value
+
+	// Compare, if set, compares both values itself when it returns ok.
+	Compare func(av, bv reflect.Value) (equal, ok bool)
+}
+
+// DiffWith is like Diff, but compares a and b as customized by opts.
+func DiffWith(a, b interface{}, opts DiffOptions) (desc []string) {
+	d := diffPrinter{
+		w:        (*sbuf)(&desc),
+		opts:     &opts,
+		aVisited: make(map[visit]visit),
+		bVisited: make(map[visit]visit),
+	}
+	d.diff(reflect.ValueOf(a), reflect.ValueOf(b))
+	return desc
+}
+
 type Logfer interface {
 	Logf(format string, a ...interface{})
 }
@@ -69,8 +99,9 @@ func Ldiff(l Logfer, a, b interface{}) {
 }
 
 type diffPrinter struct {
-	w Printfer
-	l string // label
+	w    Printfer
+	l    string       // label
+	opts *DiffOptions // Set by DiffWith.
 
 	aVisited map[visit]visit
 	bVisited map[visit]visit
@@ -104,6 +135,28 @@ func (w diffPrinter) diff(av, bv reflect.Value) {
 		return
 	}
 
+	if w.opts != nil {
+		if w.opts.Transform != nil {
+			av, bv = w.opts.Transform(av), w.opts.Transform(bv)
+		}
+		if w.opts.Compare != nil {
+			if equal, ok := w.opts.Compare(av, bv); ok {
+				if !equal {
+					// Values with their own comparer are taken as a whole.
+					w.printf("%s != %s", goSyntax(av), goSyntax(bv))
+				}
+				return
+			}
+		}
+		switch at.Kind() {
+		case reflect.Map, reflect.Slice:
+			if !w.opts.EquateEmpty && av.IsNil() != bv.IsNil() {
+				w.printf("%s != %s", goSyntax(av), goSyntax(bv))
+				return
+			}
+		}
+	}
+
 	if av.CanAddr() && bv.CanAddr() {
 		avis := visit{av.UnsafeAddr(), at}
 		bvis := visit{bv.UnsafeAddr(), bt}
@@ -197,13 +250,26 @@ func (w diffPrinter) diff(av, bv reflect.Value) {
 		}
 	case reflect.Struct:
 		for i := 0; i < av.NumField(); i++ {
-			w.relabel(at.Field(i).Name).diff(av.Field(i), bv.Field(i))
+			fw := w.relabel(at.Field(i).Name)
+			if w.opts != nil && w.opts.IgnoreField != nil && w.opts.IgnoreField(fw.l, at.Field(i)) {
+				continue
+			}
+			fw.diff(av.Field(i), bv.Field(i))
 		}
 	default:
 		panic("unknown reflect Kind: " + kind.String())
 	}
 }
 
+// goSyntax formats v as fmt does with %#v, which tells nil and empty
+// values apart, unless v can't be accessed.
+func goSyntax(v reflect.Value) string {
+	if v.CanInterface() {
+		return fmt.Sprintf("%#v", v.Interface())
+	}
+	return fmt.Sprintf("%# v", formatter{v: v, quote: true})
+}
+
 func (d diffPrinter) relabel(name string) (d1 diffPrinter) {
 	d1 = d
 	if d.l != "" && name[0] != '[' {
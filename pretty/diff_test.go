@@ -255,3 +255,28 @@ func minus(t *testing.T, s string, a, b []string) {
 		}
 	}
 }
+
+var lineDiffs = []struct {
+	a, b []string
+	exp  []string
+}{
+	{nil, nil, nil},
+	{[]string{"a", "b"}, []string{"a", "b"}, nil},
+	{[]string{"a"}, nil, []string{`-[0]: "a"`}},
+	{nil, []string{"a"}, []string{`+[0]: "a"`}},
+	{[]string{"a", "b", "c"}, []string{"a", "x", "c"}, []string{`-[1]: "b"`, `+[1]: "x"`}},
+	{[]string{"a", "b", "c"}, []string{"x", "a", "b", "c"}, []string{`+[0]: "x"`}},
+	{[]string{"a", "b", "c", "d"}, []string{"a", "c", "d", "e"}, []string{`-[1]: "b"`, `+[3]: "e"`}},
+	{[]string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"},
+		[]string{`-[0]: "a"`, `-[1]: "b"`, `+[1]: "b"`, `-[5]: "b"`, `+[5]: "c"`}},
+}
+
+func TestLineDiff(t *testing.T) {
+	for _, tt := range lineDiffs {
+		got := LineDiff(tt.a, tt.b)
+		if !reflect.DeepEqual(got, tt.exp) {
+			t.Errorf("LineDiff(%q, %q):", tt.a, tt.b)
+			diffdiff(t, got, tt.exp)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
 	"strconv"
 	"text/tabwriter"
 
@@ -37,7 +38,7 @@ func (fo formatter) passThrough(f fmt.State, c rune) {
 	s := "%"
 	for i := 0; i < 128; i++ {
 		if f.Flag(i) {
-			s += string(i)
+			s += string(rune(i))
 		}
 	}
 	if w, ok := f.Width(); ok {
@@ -124,6 +125,7 @@ func (p *printer) printValue(v reflect.Value, showType, quote bool) {
 				pp = p.indent()
 			}
 			keys := v.MapKeys()
+			sortKeys(keys)
 			for i := 0; i < v.Len(); i++ {
 				k := keys[i]
 				mv := v.MapIndex(k)
@@ -266,6 +268,37 @@ func (p *printer) printValue(v reflect.Value, showType, quote bool) {
 	}
 }
 
+// sortKeys sorts map keys, so that maps are always printed the same way.
+func sortKeys(keys []reflect.Value) {
+	sort.SliceStable(keys, func(i, j int) bool {
+		return lessKey(keys[i], keys[j])
+	})
+}
+
+func lessKey(a, b reflect.Value) bool {
+	if a.Kind() == reflect.Interface {
+		a = a.Elem()
+	}
+	if b.Kind() == reflect.Interface {
+		b = b.Elem()
+	}
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Bool:
+			return !a.Bool() && b.Bool()
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return a.Int() < b.Int()
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return a.Uint() < b.Uint()
+		case reflect.Float32, reflect.Float64:
+			return a.Float() < b.Float()
+		case reflect.String:
+			return a.String() < b.String()
+		}
+	}
+	return fmt.Sprintf("%#v", a) < fmt.Sprintf("%#v", b)
+}
+
 func canInline(t reflect.Type) bool {
 	switch t.Kind() {
 	case reflect.Map:
@@ -0,0 +1,100 @@
+package pretty
+
+import "fmt"
+
+// LineDiff returns a slice where each element describes a line found only
+// in a, as in `-[3]: "line"`, or only in b, as in `+[4]: "line"`, with the
+// index of the line in its slice.  Lines are matched with the Myers diff
+// algorithm, so lines added or removed don't make the following lines
+// differ, unlike with Diff.
+func LineDiff(a, b []string) (desc []string) {
+	for _, e := range lineEdits(a, b) {
+		if e.inA {
+			desc = append(desc, fmt.Sprintf("-[%d]: %q", e.index, a[e.index]))
+		} else {
+			desc = append(desc, fmt.Sprintf("+[%d]: %q", e.index, b[e.index]))
+		}
+	}
+	return desc
+}
+
+// lineEdit is a line found only in a, or only in b.
+type lineEdit struct {
+	inA   bool
+	index int
+}
+
+// lineEdits returns the shortest list of lines to remove from a and to
+// add from b to turn a into b, in order.
+func lineEdits(a, b []string) []lineEdit {
+	// Common leading and trailing lines are left out of the search.
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	n, m := len(a), len(b)
+	for n > start && m > start && a[n-1] == b[m-1] {
+		n--
+		m--
+	}
+	a, b = a[start:n], b[start:m]
+	n, m = len(a), len(b)
+
+	// v[k+max] is the furthest x reached on diagonal k, and trace[d] keeps
+	// v as it was before step d, for diagonals -d+1 to d-1.
+	max := n + m
+	v := make([]int, 2*max+2)
+	var trace [][]int
+	var x, y, d int
+	for d = 0; d <= max; d++ {
+		var before []int
+		if d > 0 {
+			before = append(before, v[max-d+1:max+d]...)
+		}
+		trace = append(trace, before)
+		found := false
+		for k := -d; k <= d; k += 2 {
+			if k == -d || k != d && v[max+k-1] < v[max+k+1] {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y = x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+		if found {
+			break
+		}
+	}
+
+	// Walk back from the end to find the edits.
+	edits := make([]lineEdit, d)
+	x, y = n, m
+	for ; d > 0; d-- {
+		prev := func(k int) int { return trace[d][k+d-1] }
+		k := x - y
+		var prevK int
+		if k == -d || k != d && prev(k-1) < prev(k+1) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := prev(prevK)
+		prevY := prevX - prevK
+		if prevK == k+1 {
+			edits[d-1] = lineEdit{false, start + prevY}
+		} else {
+			edits[d-1] = lineEdit{true, start + prevX}
+		}
+		x, y = prevX, prevY
+	}
+	return edits
+}
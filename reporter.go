@@ -2,6 +2,7 @@ package check
 
 import (
 	"io"
+	"strings"
 	"sync"
 
 	"github.com/iostrovok/check/formatters"
@@ -11,15 +12,18 @@ import (
 // Output writer manages atomic output writing according to settings.
 
 type outputWriter struct {
-	m                    sync.Mutex
-	writer               io.Writer
-	wroteCallProblemLast bool
-	Stream               bool
-	Verbose              bool
+	m         sync.Mutex
+	writer    io.Writer
+	formatter formatters.Formatter
+	Stream    bool
+	Verbose   bool
 }
 
-func newOutputWriter(writer io.Writer, stream, verbose bool) *outputWriter {
-	return &outputWriter{writer: writer, Stream: stream, Verbose: verbose}
+func newOutputWriter(writer io.Writer, formatter formatters.Formatter, stream, verbose bool) *outputWriter {
+	if formatter == nil {
+		formatter, _ = formatters.Lookup(formatters.DefaultFormatter)
+	}
+	return &outputWriter{writer: writer, formatter: formatter, Stream: stream, Verbose: verbose}
 }
 
 func (ow *outputWriter) Write(content []byte) (n int, err error) {
@@ -29,75 +33,78 @@ func (ow *outputWriter) Write(content []byte) (n int, err error) {
 	return
 }
 
-func (ow *outputWriter) WriteCallStarted(label string, c *C) {
-	if ow.Stream {
-		header := renderCallHeader(label, c, "", "\n")
-		ow.m.Lock()
-		ow.writer.Write([]byte(header))
-		ow.m.Unlock()
+// render writes the text returned by the formatter, holding the lock so
+// the formatter sees events one at a time.
+func (ow *outputWriter) render(event func(f formatters.Formatter) string) {
+	ow.m.Lock()
+	if out := event(ow.formatter); out != "" {
+		io.WriteString(ow.writer, out)
 	}
+	ow.m.Unlock()
+}
+
+func (ow *outputWriter) WriteSuiteStarted(suite string) {
+	ow.render(func(f formatters.Formatter) string { return f.SuiteStarted(suite) })
+}
+
+func (ow *outputWriter) WriteCallStarted(label string, c *C) {
+	d := ow.callData(label, c)
+	ow.render(func(f formatters.Formatter) string { return f.CallStarted(d) })
+}
+
+func (ow *outputWriter) WriteCallOutput(c *C, content []byte) {
+	d := ow.callData("", c)
+	ow.render(func(f formatters.Formatter) string { return f.Output(d, string(content)) })
 }
 
 func (ow *outputWriter) WriteCallProblem(label string, c *C) {
-	var prefix string
-	if !ow.Stream {
-		prefix = "\n-----------------------------------" +
-			"-----------------------------------\n"
-	}
-	header := renderCallHeader(label, c, prefix, "\n\n")
-	ow.m.Lock()
-	ow.wroteCallProblemLast = true
-	ow.writer.Write([]byte(header))
-	if !ow.Stream {
-		c.logb.WriteTo(ow.writer)
+	d := ow.callData(label, c)
+	d.Problem = true
+	if ow.Stream {
+		d.StdOut = c.GetTestLog()
+	} else {
+		// Consume the log, so it isn't shown again by the test
+		// sharing it with a failed fixture.
+		var log strings.Builder
+		c.logb.WriteTo(&log)
+		d.StdOut = log.String()
 	}
-	ow.m.Unlock()
+	ow.render(func(f formatters.Formatter) string { return f.CallDone(d) })
 }
 
 func (ow *outputWriter) WriteCallSuccess(label string, c *C) {
-	if ow.Stream || (ow.Verbose && c.kind == testKd) {
-		// TODO Use a buffer here.
-		var suffix string
-		if c.reason != "" {
-			suffix = " (" + c.reason + ")"
-		}
-		if c.status() == succeededSt {
-			suffix += "\t" + c.timerString()
-		}
-		suffix += "\n"
-		if ow.Stream {
-			suffix += "\n"
-		}
-		header := renderCallHeader(label, c, "", suffix)
-		ow.m.Lock()
-		// Resist temptation of using line as prefix above due to race.
-		if !ow.Stream && ow.wroteCallProblemLast {
-			header = "\n-----------------------------------" +
-				"-----------------------------------\n" +
-				header
-		}
-		ow.wroteCallProblemLast = false
-		ow.writer.Write([]byte(header))
-		ow.m.Unlock()
-	}
+	d := ow.callData(label, c)
+	d.StdOut = c.GetTestLog()
+	ow.render(func(f formatters.Formatter) string { return f.CallDone(d) })
 }
 
-func renderCallHeader(label string, c *C, prefix, suffix string) string {
+// callData describes c for the formatter.  The log is left out, since
+// it's only worth copying for finished calls.
+func (ow *outputWriter) callData(label string, c *C) formatters.Data {
 	pc := c.method.PC()
 
 	d := formatters.Data{
 		StartTime:    c.startTime,
-		Duration:     c.duration,
+		Duration:     c.elapsed(),
 		TestName:     c.testName,
-		StdOut:       c.GetTestLog(),
+		Suite:        c.method.suiteName(),
+		Method:       c.method.Info.Name + c.subtestPath(),
+		Fixture:      c.kind == fixtureKd,
+		Reason:       c.reason,
 		FuncPath:     niceFuncPath(pc),
-		FuncName:     niceFuncName(pc),
-		Prefix:       prefix,
+		Package:      niceFuncPackage(pc),
+		FuncName:     niceFuncName(pc) + c.subtestPath(),
+		Parent:       "TestingT",
 		Label:        label,
-		Suffix:       suffix,
-		Formatter:    c.formatter,
+		Stream:       ow.Stream,
+		Verbose:      ow.Verbose,
 		FormatPrefix: c.formatPrefix,
 	}
-
-	return formatters.Render(d)
+	if c.testingT != nil {
+		d.Parent = c.testingT.Name()
+	}
+	if c.status() == succeededSt {
+		d.Timer = c.timerString()
+	}
+	return d
 }
@@ -170,3 +170,10 @@ func (s *reporterS) TestWriteCallProblemWithStreamFlag_333(c *C) {
 	expected := fmt.Sprintf("%s: %s:\\d+: %s\n\n", testLabel, s.testFile, c.TestName())
 	c.Assert(output.value, Matches, expected)
 }
+
+func (s *reporterS) TestFuncPackage(c *C) {
+	c.Check(FuncPackage("github.com/iostrovok/check.(*C).Run"), Equals, "github.com/iostrovok/check")
+	c.Check(FuncPackage("github.com/iostrovok/check_test.(*reporterS).TestWrite"), Equals, "github.com/iostrovok/check")
+	c.Check(FuncPackage("gopkg.in/foo%2ev1.Func"), Equals, "gopkg.in/foo.v1")
+	c.Check(FuncPackage("gopkg.in/foo%2ev1_test.(*S).Test.func1"), Equals, "gopkg.in/foo.v1")
+}
@@ -4,7 +4,10 @@ import (
 	"bufio"
 	"flag"
 	"fmt"
+	"io"
+	"math/rand"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -44,9 +47,20 @@ var (
 	newBenchMem    = flag.Bool("check.bmem", false, "Report memory benchmarks")
 	newListFlag    = flag.Bool("check.list", false, "List the names of all tests that will be run")
 	newWorkFlag    = flag.Bool("check.work", false, "Display and do not remove the test working directory")
+	newParallel    = flag.Int("check.parallel", 0, "Maximum number of tests marked with C.Parallel to run simultaneously (default GOMAXPROCS)")
+	newTimeout     = flag.Duration("check.timeout", 0, "Abandon any test running for longer than the given duration (default no timeout)")
+	newFailFast    = flag.Bool("check.failfast", false, "Do not start new tests after the first test failure")
+	newCount       = flag.Int("check.count", 1, "Run all tests the given number of times")
+	newUntilFail   = flag.Bool("check.until-fail", false, "Run all tests again and again until some test fails")
+	newRetries     = flag.Int("check.retries", 0, "Run failed tests again up to the given number of times, reporting them as FLAKY if they pass (tests calling C.Parallel aren't retried)")
+	newLeaks       = flag.Bool("check.leaks", false, "Fail tests and suites which leave goroutines behind")
+	newUpdateFlag  = flag.Bool("check.update", false, "Write the obtained values to golden files rather than comparing them")
+	newUpdateSnaps = flag.Bool("check.update-snapshots", false, "Write the values matched with C.MatchSnapshot to the snapshot files, and remove the obsolete snapshots")
+	newShuffle     = flag.String("check.shuffle", "off", "Randomize the execution order of suites and tests: 'off', 'on' or the seed to use")
 
-	formattedMessageFlag        = flag.String("check.format", "", "Display formatted messages. Now 'teamcity' and 'json' are only supported.")
+	formattedMessageFlag        = flag.String("check.format", "", "Display formatted messages with the given registered formatter (e.g. 'teamcity', 'json' or 'junit').")
 	formatMessageNamePrefixFlag = flag.String("check.name", "", "Add name prefix to formatted messages.")
+	reportOutputFlag            = flag.String("check.output", "", "Write whole-run reports of formatted messages (e.g. 'junit') to the given file.")
 )
 
 // TestingT runs all test suites registered with the Suite function,
@@ -57,18 +71,38 @@ func TestingT(testingT *testing.T) {
 	if benchTime == 1*time.Second {
 		benchTime = *oldBenchTime
 	}
+	formatter, err := formatters.F(formattedMessageFlag)
+	if err != nil {
+		testingT.Fatal(err)
+	}
 	conf := &RunConf{
 		Filter:        *oldFilterFlag + *newFilterFlag,
 		Verbose:       *oldVerboseFlag || *newVerboseFlag,
-		Stream:        *oldStreamFlag || *newStreamFlag || *formattedMessageFlag != "",
+		Stream:        *oldStreamFlag || *newStreamFlag || isStreamFormat(*formattedMessageFlag, formatter),
 		Benchmark:     *oldBenchFlag || *newBenchFlag,
 		BenchmarkTime: benchTime,
 		BenchmarkMem:  *newBenchMem,
 		KeepWorkDir:   *oldWorkFlag || *newWorkFlag,
+		Parallel:      *newParallel,
+		TestTimeout:   *newTimeout,
+		Shuffle:       *newShuffle,
+		FailFast:      *newFailFast,
+		Count:         *newCount,
+		UntilFail:     *newUntilFail,
+		Retries:       *newRetries,
+		DetectLeaks:   *newLeaks,
 		testingT:      testingT,
-		formatter:     formatters.F(formattedMessageFlag),
+		Formatter:     formatter,
 		formatPrefix:  *formatMessageNamePrefixFlag,
 	}
+	if *reportOutputFlag != "" {
+		f, err := os.Create(*reportOutputFlag)
+		if err != nil {
+			testingT.Fatal(err)
+		}
+		defer f.Close()
+		conf.ReportOutput = f
+	}
 	if *oldListFlag || *newListFlag {
 		w := bufio.NewWriter(os.Stdout)
 		for _, name := range ListAll(conf) {
@@ -87,17 +121,113 @@ func TestingT(testingT *testing.T) {
 // RunAll runs all test suites registered with the Suite function, using the
 // provided run configuration.
 func RunAll(runConf *RunConf) *CheckTestResult {
-	result := CheckTestResult{}
-	for _, suite := range allSuites {
-		result.Add(Run(suite, runConf))
+	conf, finish := startRun(runConf)
+	suites := allSuites
+	if seed, shuffle, _ := shuffleSeed(conf.Shuffle); shuffle {
+		suites = append([]any(nil), allSuites...)
+		rand.New(rand.NewSource(seed)).Shuffle(len(suites), func(i, j int) {
+			suites[i], suites[j] = suites[j], suites[i]
+		})
+	}
+	result := runSuites(suites, conf)
+	finish(result)
+	return result
+}
+
+// runSuites runs the given suites one after the other.  When running the
+// tests until some test fails, each round runs all the suites once, rather
+// than the first suite repeating forever.
+func runSuites(suites []any, conf *RunConf) *CheckTestResult {
+	suiteConf := *conf
+	suiteConf.UntilFail = false
+	result := &CheckTestResult{}
+	for {
+		round := CheckTestResult{}
+		for _, suite := range suites {
+			round.Add(Run(suite, &suiteConf))
+		}
+		result.Add(&round)
+		if !conf.UntilFail || !round.Passed() || len(round.Tests) == 0 {
+			return result
+		}
 	}
-	return &result
 }
 
 // Run runs the provided test suite using the provided run configuration.
 func Run(suite any, runConf *RunConf) *CheckTestResult {
-	runner := newSuiteRunner(suite, runConf)
-	return runner.run()
+	conf, finish := startRun(runConf)
+	runner := newSuiteRunner(suite, conf)
+	result := runner.run()
+	finish(result)
+	return result
+}
+
+// Formats selected by name are streamed, unless the formatter says otherwise.
+func isStreamFormat(name string, formatter formatters.Formatter) bool {
+	if name == "" {
+		return false
+	}
+	if f, ok := formatter.(formatters.StreamFormatter); ok {
+		return f.Stream()
+	}
+	return true
+}
+
+// shuffleSeed parses RunConf.Shuffle, returning whether suites and tests
+// are to be run in random order, and the seed to use.
+func shuffleSeed(shuffle string) (seed int64, ok bool, err error) {
+	switch shuffle {
+	case "", "off":
+		return 0, false, nil
+	case "on":
+		return time.Now().UnixNano(), true, nil
+	}
+	seed, err = strconv.ParseInt(shuffle, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("Bad shuffle value: %q (want \"on\", \"off\" or a seed)", shuffle)
+	}
+	return seed, true, nil
+}
+
+// startRun returns the configuration shared by all suites of a run, with
+// a single formatter for all of them, and a function reporting the end of
+// the run to the formatter.  Runs nested in another run are left alone.
+func startRun(runConf *RunConf) (*RunConf, func(result *CheckTestResult)) {
+	if runConf != nil && runConf.running {
+		return runConf, func(*CheckTestResult) {}
+	}
+	var conf RunConf
+	if runConf != nil {
+		conf = *runConf
+	}
+	conf.running = true
+	if conf.Formatter == nil {
+		conf.Formatter, _ = formatters.Lookup(formatters.DefaultFormatter)
+	}
+	if conf.FailFast {
+		conf.failedFast = new(uint32)
+	}
+	if conf.Shuffle == "on" {
+		// All suites share the seed, so it's enough to reproduce the run.
+		conf.Shuffle = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	start := time.Now()
+	return &conf, func(result *CheckTestResult) {
+		report := conf.Formatter.RunDone(result.summary(start))
+		if report == "" {
+			return
+		}
+		w := conf.ReportOutput
+		if w == nil {
+			w = conf.Output
+		}
+		if w == nil {
+			w = os.Stdout
+		}
+		if _, err := io.WriteString(w, report); err != nil && result.RunError == nil {
+			result.RunError = err
+		}
+	}
 }
 
 // ListAll returns the names of all the test functions registered with the
@@ -132,6 +262,13 @@ func (r *CheckTestResult) Add(other *CheckTestResult) {
 	r.FixturePanicked += other.FixturePanicked
 	r.ExpectedFailures += other.ExpectedFailures
 	r.Missed += other.Missed
+	r.TimedOut += other.TimedOut
+	r.Flaky += other.Flaky
+	r.Tests = append(r.Tests, other.Tests...)
+	r.ObsoleteSnapshots = append(r.ObsoleteSnapshots, other.ObsoleteSnapshots...)
+	if r.ShuffleSeed == nil {
+		r.ShuffleSeed = other.ShuffleSeed
+	}
 	if r.WorkDir != "" && other.WorkDir != "" {
 		r.WorkDir += ":" + other.WorkDir
 	} else if other.WorkDir != "" {
@@ -142,7 +279,7 @@ func (r *CheckTestResult) Add(other *CheckTestResult) {
 func (r *CheckTestResult) Passed() bool {
 	return (r.Failed == 0 && r.Panicked == 0 &&
 		r.FixturePanicked == 0 && r.Missed == 0 &&
-		r.RunError == nil)
+		r.TimedOut == 0 && r.RunError == nil)
 }
 
 func (r *CheckTestResult) String() string {
@@ -152,7 +289,7 @@ func (r *CheckTestResult) String() string {
 
 	var value string
 	if r.Failed == 0 && r.Panicked == 0 && r.FixturePanicked == 0 &&
-		r.Missed == 0 {
+		r.Missed == 0 && r.TimedOut == 0 {
 		value = "OK: "
 	} else {
 		value = "OOPS: "
@@ -176,8 +313,39 @@ func (r *CheckTestResult) String() string {
 	if r.Missed != 0 {
 		value += fmt.Sprintf(", %d MISSED", r.Missed)
 	}
+	if r.TimedOut != 0 {
+		value += fmt.Sprintf(", %d TIMEOUT", r.TimedOut)
+	}
+	if r.Flaky != 0 {
+		value += fmt.Sprintf(", %d FLAKY", r.Flaky)
+	}
+	if r.ShuffleSeed != nil {
+		value += fmt.Sprintf(", shuffle seed %d", *r.ShuffleSeed)
+	}
 	if r.WorkDir != "" {
 		value += "\nWORK=" + r.WorkDir
 	}
 	return value
 }
+
+// summary describes the result for the formatter, as a run started at the
+// given time.
+func (r *CheckTestResult) summary(start time.Time) formatters.Summary {
+	return formatters.Summary{
+		StartTime:        start,
+		Duration:         time.Since(start),
+		Succeeded:        r.Succeeded,
+		Failed:           r.Failed,
+		Skipped:          r.Skipped,
+		Panicked:         r.Panicked,
+		FixturePanicked:  r.FixturePanicked,
+		ExpectedFailures: r.ExpectedFailures,
+		Missed:           r.Missed,
+		TimedOut:         r.TimedOut,
+		Flaky:            r.Flaky,
+		Passed:           r.Passed(),
+		Text:             r.String(),
+
+		ObsoleteSnapshots: r.ObsoleteSnapshots,
+	}
+}
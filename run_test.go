@@ -3,11 +3,19 @@
 package check_test
 
 import (
+	stdjson "encoding/json"
+	"encoding/xml"
 	"errors"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	. "github.com/iostrovok/check"
+	"github.com/iostrovok/check/formatters"
 )
 
 var runnerS = Suite(&RunS{})
@@ -418,3 +426,911 @@ func (s *RunS) TestKeepWorkDir(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(stat.IsDir(), Equals, true)
 }
+
+// -----------------------------------------------------------------------
+// Verify that tests marked with C.Parallel run concurrently.
+
+type ParallelHelper struct {
+	m          sync.Mutex
+	calls      []string
+	barrier    sync.WaitGroup
+	running    int32
+	maxRunning int32
+}
+
+func (s *ParallelHelper) trace(name string) {
+	s.m.Lock()
+	s.calls = append(s.calls, name)
+	s.m.Unlock()
+}
+
+func (s *ParallelHelper) SetUpTest(c *C) {
+	s.trace("SetUpTest")
+}
+
+func (s *ParallelHelper) TearDownTest(c *C) {
+	s.trace("TearDownTest")
+}
+
+func (s *ParallelHelper) parallel(c *C) {
+	c.Parallel()
+	n := atomic.AddInt32(&s.running, 1)
+	defer atomic.AddInt32(&s.running, -1)
+	s.m.Lock()
+	if n > s.maxRunning {
+		s.maxRunning = n
+	}
+	s.m.Unlock()
+
+	// Wait until all parallel tests are running at once.
+	s.barrier.Done()
+	done := make(chan bool)
+	go func() {
+		s.barrier.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(200 * time.Millisecond):
+	}
+	s.trace("Parallel")
+}
+
+func (s *ParallelHelper) Test1(c *C) {
+	s.parallel(c)
+}
+
+func (s *ParallelHelper) Test2(c *C) {
+	s.trace("Sequential")
+}
+
+func (s *ParallelHelper) Test3(c *C) {
+	s.parallel(c)
+}
+
+func (s *ParallelHelper) Test4(c *C) {
+	s.parallel(c)
+}
+
+func (s *RunS) TestParallel(c *C) {
+	helper := &ParallelHelper{}
+	helper.barrier.Add(3)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Parallel: 3})
+	c.Check(result.Succeeded, Equals, 4)
+	c.Check(result.Failed, Equals, 0)
+	c.Check(helper.maxRunning, Equals, int32(3))
+
+	count := map[string]int{}
+	for _, call := range helper.calls {
+		count[call]++
+	}
+	c.Check(count, DeepEquals, map[string]int{
+		"SetUpTest":    4,
+		"TearDownTest": 4,
+		"Sequential":   1,
+		"Parallel":     3,
+	})
+}
+
+func (s *RunS) TestParallelLimit(c *C) {
+	helper := &ParallelHelper{}
+	helper.barrier.Add(3)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Parallel: 1})
+	c.Check(result.Succeeded, Equals, 4)
+	c.Check(helper.maxRunning, Equals, int32(1))
+}
+
+type ParallelSetenvHelper struct{}
+
+func (s *ParallelSetenvHelper) TestChdir(c *C) {
+	c.Chdir(".")
+	c.Parallel()
+}
+
+func (s *ParallelSetenvHelper) TestSetenv(c *C) {
+	c.Setenv("CHECK_PARALLEL_TEST", "set")
+	c.Parallel()
+}
+
+func (s *RunS) TestParallelAfterSetenv(c *C) {
+	output := String{}
+	result := Run(&ParallelSetenvHelper{}, &RunConf{Output: &output})
+	c.Check(result.Panicked, Equals, 2)
+	c.Check(output.value, Matches, "(?s).*Parallel can't be used by tests calling Chdir.*")
+	c.Check(output.value, Matches, "(?s).*Parallel can't be used by tests calling Setenv.*")
+}
+
+type ParallelFixtureHelper struct{}
+
+func (s *ParallelFixtureHelper) SetUpTest(c *C) {
+	c.Parallel()
+}
+
+func (s *ParallelFixtureHelper) Test1(c *C) {}
+
+func (s *RunS) TestParallelFromFixture(c *C) {
+	output := String{}
+	result := Run(&ParallelFixtureHelper{}, &RunConf{Output: &output})
+	c.Check(result.FixturePanicked, Equals, 1)
+	c.Check(result.Missed, Equals, 1)
+	c.Check(output.value, Matches, "(?s).*Parallel must be called from within a test method.*")
+}
+
+// -----------------------------------------------------------------------
+// Verify that tests running for too long are abandoned.
+
+type TimeoutHelper struct {
+	m       sync.Mutex
+	calls   []string
+	release chan bool
+	timeout time.Duration
+}
+
+func (s *TimeoutHelper) Timeout() time.Duration {
+	return s.timeout
+}
+
+func (s *TimeoutHelper) trace(name string) {
+	s.m.Lock()
+	s.calls = append(s.calls, name)
+	s.m.Unlock()
+}
+
+func (s *TimeoutHelper) TearDownTest(c *C) {
+	s.trace("TearDownTest")
+}
+
+func (s *TimeoutHelper) Test1(c *C) {
+	s.trace("Test1")
+	<-s.release
+}
+
+func (s *TimeoutHelper) Test2(c *C) {
+	s.trace("Test2")
+}
+
+func (s *RunS) TestTimeout(c *C) {
+	helper := &TimeoutHelper{release: make(chan bool)}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, TestTimeout: 50 * time.Millisecond})
+	c.Check(result.Succeeded, Equals, 1)
+	c.Check(result.TimedOut, Equals, 1)
+	c.Check(result.Passed(), Equals, false)
+	c.Check(result.String(), Equals, "OOPS: 1 passed, 1 TIMEOUT")
+
+	helper.m.Lock()
+	c.Check(helper.calls, DeepEquals, []string{"Test1", "TearDownTest", "Test2", "TearDownTest"})
+	helper.m.Unlock()
+
+	expected := "(?s)\n-+\n" +
+		"TIMEOUT: run_test\\.go:[0-9]+: TimeoutHelper\\.Test1\n\n" +
+		"\\.\\.\\. Timeout: still running after 50ms\n" +
+		"\\.\\.\\. Goroutines:\n\n" +
+		"goroutine .*\\(\\*TimeoutHelper\\)\\.Test1.*"
+	c.Check(output.value, Matches, expected)
+}
+
+func (s *RunS) TestTimeoutPerSuite(c *C) {
+	helper := &TimeoutHelper{release: make(chan bool), timeout: 50 * time.Millisecond}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, TestTimeout: time.Hour})
+	c.Check(result.Succeeded, Equals, 1)
+	c.Check(result.TimedOut, Equals, 1)
+}
+
+type SetUpTimeoutHelper struct {
+	TimeoutHelper
+}
+
+func (s *SetUpTimeoutHelper) SetUpTest(c *C) {
+	if c.TestName() == "SetUpTimeoutHelper.Test2" {
+		s.trace("SetUpTest")
+		<-s.release
+	}
+}
+
+func (s *SetUpTimeoutHelper) Test1(c *C) {
+	s.trace("Test1")
+}
+
+func (s *RunS) TestTimeoutInSetUpTest(c *C) {
+	helper := &SetUpTimeoutHelper{TimeoutHelper{release: make(chan bool)}}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, TestTimeout: 50 * time.Millisecond})
+	c.Check(result.Succeeded, Equals, 1)
+	c.Check(result.TimedOut, Equals, 1)
+	c.Check(result.Missed, Equals, 1)
+
+	helper.m.Lock()
+	c.Check(helper.calls, DeepEquals, []string{"Test1", "TearDownTest", "SetUpTest", "TearDownTest"})
+	helper.m.Unlock()
+
+	expected := "(?s)\n-+\n" +
+		"TIMEOUT: run_test\\.go:[0-9]+: SetUpTimeoutHelper\\.SetUpTest\n\n" +
+		"\\.\\.\\. Timeout: still running after 50ms\n" +
+		"\\.\\.\\. Goroutines:\n\n" +
+		"goroutine .*\\(\\*SetUpTimeoutHelper\\)\\.SetUpTest.*"
+	c.Check(output.value, Matches, expected)
+}
+
+func (s *RunS) TestPrintTimedOut(c *C) {
+	result := &CheckTestResult{TimedOut: 5}
+	c.Check(result.String(), Equals, "OOPS: 0 passed, 5 TIMEOUT")
+}
+
+// -----------------------------------------------------------------------
+// Verify that fail-fast mode stops starting tests after a failure.
+
+type FailFastHelper struct {
+	calls []string
+}
+
+func (s *FailFastHelper) TearDownTest(c *C) {
+	s.calls = append(s.calls, "TearDownTest")
+}
+
+func (s *FailFastHelper) TearDownSuite(c *C) {
+	s.calls = append(s.calls, "TearDownSuite")
+}
+
+func (s *FailFastHelper) TestA(c *C) {
+	s.calls = append(s.calls, "TestA")
+}
+
+func (s *FailFastHelper) TestB(c *C) {
+	s.calls = append(s.calls, "TestB")
+	c.Fail()
+}
+
+func (s *FailFastHelper) TestC(c *C) {
+	s.calls = append(s.calls, "TestC")
+}
+
+func (s *FailFastHelper) TestD(c *C) {
+	s.calls = append(s.calls, "TestD")
+}
+
+func (s *RunS) TestFailFast(c *C) {
+	helper := &FailFastHelper{}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, FailFast: true})
+	c.Check(helper.calls, DeepEquals, []string{
+		"TestA", "TearDownTest",
+		"TestB", "TearDownTest",
+		"TearDownSuite",
+	})
+	c.Check(result.String(), Equals, "OOPS: 1 passed, 1 FAILED, 2 MISSED")
+	c.Check(result.Tests[2].Method, Equals, "TestC")
+	c.Check(result.Tests[2].Status, Equals, "MISS")
+}
+
+func (s *RunS) TestFailFastDisabled(c *C) {
+	helper := &FailFastHelper{}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output})
+	c.Check(result.String(), Equals, "OOPS: 3 passed, 1 FAILED")
+}
+
+// -----------------------------------------------------------------------
+// Verify that tests may be run repeatedly, and retried when they fail.
+
+type RepeatHelper struct {
+	calls   []string
+	failOn  int // Fail TestA when it runs for this time.
+	flakyOn int // Fail TestB when it runs for this time.
+	runsA   int
+	runsB   int
+}
+
+func (s *RepeatHelper) SetUpSuite(c *C) {
+	s.calls = append(s.calls, "SetUpSuite")
+}
+
+func (s *RepeatHelper) SetUpTest(c *C) {
+	s.calls = append(s.calls, "SetUpTest")
+}
+
+func (s *RepeatHelper) TestA(c *C) {
+	s.calls = append(s.calls, "TestA")
+	s.runsA++
+	if s.runsA == s.failOn {
+		c.Fail()
+	}
+}
+
+func (s *RepeatHelper) TestB(c *C) {
+	s.calls = append(s.calls, "TestB")
+	s.runsB++
+	if s.runsB <= s.flakyOn {
+		c.Fail()
+	}
+}
+
+func (s *RunS) TestCount(c *C) {
+	helper := &RepeatHelper{}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Count: 2})
+	c.Check(helper.calls, DeepEquals, []string{
+		"SetUpSuite",
+		"SetUpTest", "TestA", "SetUpTest", "TestB",
+		"SetUpTest", "TestA", "SetUpTest", "TestB",
+	})
+	c.Check(result.String(), Equals, "OK: 4 passed")
+	c.Check(result.Tests, HasLen, 4)
+}
+
+func (s *RunS) TestUntilFail(c *C) {
+	helper := &RepeatHelper{failOn: 3}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, UntilFail: true})
+	c.Check(helper.runsA, Equals, 3)
+	c.Check(helper.runsB, Equals, 3)
+	c.Check(result.String(), Equals, "OOPS: 5 passed, 1 FAILED")
+}
+
+func (s *RunS) TestUntilFailAllSuites(c *C) {
+	helper1 := &RepeatHelper{failOn: 3}
+	helper2 := &RepeatHelper{}
+	output := String{}
+	result := RunSuites([]any{helper1, helper2}, &RunConf{Output: &output, UntilFail: true})
+	c.Check(helper1.runsA, Equals, 3)
+	c.Check(helper2.runsA, Equals, 3)
+	c.Check(strings.Count(strings.Join(helper2.calls, " "), "SetUpSuite"), Equals, 3) // Once per round.
+	c.Check(result.String(), Equals, "OOPS: 11 passed, 1 FAILED")
+}
+
+func (s *RunS) TestRetries(c *C) {
+	helper := &RepeatHelper{failOn: 1, flakyOn: 5}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Retries: 2})
+	c.Check(helper.runsA, Equals, 2)
+	c.Check(helper.runsB, Equals, 3)
+	c.Check(result.String(), Equals, "OOPS: 0 passed, 1 FAILED, 1 FLAKY")
+	c.Check(result.Passed(), Equals, false)
+
+	var statuses []string
+	for _, test := range result.Tests {
+		statuses = append(statuses, test.Method+" "+test.Status)
+	}
+	c.Check(statuses, DeepEquals, []string{
+		"TestA RETRY", "TestA FLAKY",
+		"TestB RETRY", "TestB RETRY", "TestB FAIL",
+	})
+	c.Check(output.value, Matches, "(?s)\n-+\nRETRY: run_test\\.go:[0-9]+: RepeatHelper\\.TestA\n.*")
+}
+
+func (s *RunS) TestRetriesFlakyPasses(c *C) {
+	helper := &RepeatHelper{flakyOn: 1}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Retries: 1})
+	c.Check(result.String(), Equals, "OK: 1 passed, 1 FLAKY")
+	c.Check(result.Passed(), Equals, true)
+}
+
+// -----------------------------------------------------------------------
+// Verify subtests created with C.Run.
+
+type SubtestHelper struct {
+	ran []string
+	ok  []bool
+}
+
+func (s *SubtestHelper) TestNested(c *C) {
+	c.Run("outer", func(c *C) {
+		c.Run("inner", func(c *C) {
+			s.ran = append(s.ran, "outer/inner")
+		})
+	})
+}
+
+func (s *SubtestHelper) TestTable(c *C) {
+	for _, name := range []string{"one", "bad row", "two", "two"} {
+		ok := c.Run(name, func(c *C) {
+			s.ran = append(s.ran, name)
+			c.Log("Running " + name)
+			if name == "bad row" {
+				c.Assert(1, Equals, 2)
+				c.Log("Unreachable")
+			}
+		})
+		s.ok = append(s.ok, ok)
+	}
+}
+
+func (s *RunS) TestSubtests(c *C) {
+	helper := &SubtestHelper{}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output})
+	c.Check(helper.ran, DeepEquals, []string{"outer/inner", "one", "bad row", "two", "two"})
+	c.Check(helper.ok, DeepEquals, []bool{true, false, true, true})
+	c.Check(result.String(), Equals, "OOPS: 1 passed, 1 FAILED")
+
+	var statuses []string
+	for _, test := range result.Tests {
+		statuses = append(statuses, test.Method+" "+test.Status)
+	}
+	c.Check(statuses, DeepEquals, []string{
+		"TestNested/outer/inner PASS",
+		"TestNested/outer PASS",
+		"TestNested PASS",
+		"TestTable/one PASS",
+		"TestTable/bad_row FAIL",
+		"TestTable/two PASS",
+		"TestTable/two#01 PASS",
+		"TestTable FAIL",
+	})
+	c.Check(result.Tests[4].Log, Matches, "(?s)Running bad row\n.*obtained int = 1\n.*")
+	c.Check(result.Tests[4].Log, Not(Matches), "(?s).*Unreachable.*")
+
+	expected := "(?s)\n-+\nFAIL: run_test\\.go:[0-9]+: SubtestHelper\\.TestTable/bad_row\n.*" +
+		"\n-+\nFAIL: run_test\\.go:[0-9]+: SubtestHelper\\.TestTable\n\n$"
+	c.Check(output.value, Matches, expected)
+}
+
+func (s *RunS) TestSubtestsFilter(c *C) {
+	helper := &SubtestHelper{}
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Filter: "TestTable/two"})
+	c.Check(helper.ran, DeepEquals, []string{"two", "two"})
+	c.Check(result.String(), Equals, "OK: 1 passed")
+
+	helper = &SubtestHelper{}
+	Run(helper, &RunConf{Output: &output, Filter: "Nested/outer/none"})
+	c.Check(helper.ran, HasLen, 0)
+}
+
+// -----------------------------------------------------------------------
+// Verify the detection of leaked goroutines.
+
+type LeakHelper struct {
+	release chan bool
+	detect  bool
+}
+
+func (s *LeakHelper) DetectLeaks() bool {
+	return s.detect
+}
+
+func (s *LeakHelper) IgnoreLeaks() []string {
+	return []string{"github.com/iostrovok/check_test.(*LeakHelper).background"}
+}
+
+func (s *LeakHelper) background() {
+	<-s.release
+}
+
+func (s *LeakHelper) SetUpSuite(c *C) {
+	go func() { <-s.release }()
+}
+
+func (s *LeakHelper) TestLeak(c *C) {
+	go func() { <-s.release }()
+}
+
+func (s *LeakHelper) TestShortLived(c *C) {
+	go time.Sleep(10 * time.Millisecond)
+}
+
+func (s *LeakHelper) TestIgnored(c *C) {
+	go s.background()
+}
+
+func (s *RunS) TestLeaks(c *C) {
+	defer SetLeakGracePeriod(100 * time.Millisecond)()
+	helper := &LeakHelper{release: make(chan bool), detect: true}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output})
+	c.Check(result.String(), Equals, "OOPS: 2 passed, 2 FAILED")
+
+	expected := "(?s)\n-+\n" +
+		"FAIL: run_test\\.go:[0-9]+: LeakHelper\\.TestLeak\n\n" +
+		"\\.\\.\\. Leaked goroutines:\n\n" +
+		"goroutine [0-9]+ \\[chan receive\\]:\n" +
+		"[^\n]*LeakHelper\\)\\.TestLeak\\.func1.*" +
+		"\n-+\n" +
+		"FAIL: run_test\\.go:[0-9]+: LeakHelper\\.SetUpSuite\n\n" +
+		"\\.\\.\\. Leaked goroutines:\n\n" +
+		"goroutine [0-9]+ \\[chan receive\\]:\n" +
+		"[^\n]*LeakHelper\\)\\.SetUpSuite\\.func1.*"
+	c.Check(output.value, Matches, expected)
+	c.Check(strings.Count(output.value, "Leaked goroutines"), Equals, 2)
+}
+
+type LeakTearDownHelper struct {
+	LeakHelper
+}
+
+func (s *LeakTearDownHelper) TearDownSuite(c *C) {}
+
+func (s *RunS) TestLeaksInTearDownSuite(c *C) {
+	defer SetLeakGracePeriod(100 * time.Millisecond)()
+	helper := &LeakTearDownHelper{LeakHelper{release: make(chan bool), detect: true}}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, Stream: true})
+	c.Check(result.String(), Equals, "OOPS: 2 passed, 2 FAILED")
+
+	// Reported by TearDownSuite itself, and each fixture only once.
+	expected := "(?s).*" +
+		"START: run_test\\.go:[0-9]+: LeakTearDownHelper\\.TearDownSuite\n" +
+		"\\.\\.\\. Leaked goroutines:\n\n" +
+		"goroutine [0-9]+ \\[chan receive\\]:\n" +
+		"[^\n]*LeakHelper\\)\\.SetUpSuite\\.func1.*" +
+		"FAIL: run_test\\.go:[0-9]+: LeakTearDownHelper\\.TearDownSuite\n\n$"
+	c.Check(output.value, Matches, expected)
+	c.Check(strings.Count(output.value, "START: "), Equals, 5)
+	c.Check(strings.Count(output.value, "SetUpSuite\n"), Equals, 1)
+	c.Check(strings.Count(output.value, "TearDownSuite\n"), Equals, 2)
+}
+
+func (s *RunS) TestLeaksDisabled(c *C) {
+	helper := &LeakHelper{release: make(chan bool)}
+	defer close(helper.release)
+	output := String{}
+	result := Run(helper, &RunConf{Output: &output, DetectLeaks: true})
+	c.Check(result.String(), Equals, "OK: 3 passed")
+}
+
+// -----------------------------------------------------------------------
+// Verify that tests may be run in a random, yet reproducible, order.
+
+type ShuffleHelper struct{}
+
+func (s *ShuffleHelper) TestA(c *C) {}
+func (s *ShuffleHelper) TestB(c *C) {}
+func (s *ShuffleHelper) TestC(c *C) {}
+func (s *ShuffleHelper) TestD(c *C) {}
+func (s *ShuffleHelper) TestE(c *C) {}
+func (s *ShuffleHelper) TestF(c *C) {}
+func (s *ShuffleHelper) TestG(c *C) {}
+func (s *ShuffleHelper) TestH(c *C) {}
+
+func runShuffled(shuffle string) (result *CheckTestResult, order string) {
+	output := String{}
+	result = Run(&ShuffleHelper{}, &RunConf{Output: &output, Shuffle: shuffle})
+	for _, test := range result.Tests {
+		order += strings.TrimPrefix(test.Method, "Test")
+	}
+	return result, order
+}
+
+func (s *RunS) TestShuffle(c *C) {
+	_, order := runShuffled("off")
+	c.Check(order, Equals, "ABCDEFGH")
+
+	result, order := runShuffled("42")
+	c.Check(order, Not(Equals), "ABCDEFGH")
+	c.Assert(result.ShuffleSeed, NotNil)
+	c.Check(*result.ShuffleSeed, Equals, int64(42))
+	c.Check(result.String(), Equals, "OK: 8 passed, shuffle seed 42")
+
+	_, again := runShuffled("42")
+	c.Check(again, Equals, order)
+
+	result, order = runShuffled("on")
+	c.Assert(result.ShuffleSeed, NotNil)
+	_, again = runShuffled(strconv.FormatInt(*result.ShuffleSeed, 10))
+	c.Check(again, Equals, order)
+}
+
+func (s *RunS) TestShuffleBadValue(c *C) {
+	result, _ := runShuffled("sometimes")
+	c.Check(result.String(), Equals, `ERROR: Bad shuffle value: "sometimes" (want "on", "off" or a seed)`)
+}
+
+// -----------------------------------------------------------------------
+// Verify the results of every single test.
+
+type TestResultsHelper struct{}
+
+func (s *TestResultsHelper) TestExpectedFailure(c *C) {
+	c.ExpectFailure("Known bug")
+	c.Check(1, Equals, 2)
+}
+
+func (s *TestResultsHelper) TestFail(c *C) {
+	c.Log("Before failing")
+	c.Check(1, Equals, 2)
+	c.Check(3, Equals, 4)
+}
+
+func (s *TestResultsHelper) TestPass(c *C) {
+	c.Log("Passing")
+}
+
+func (s *TestResultsHelper) TestSkip(c *C) {
+	c.Skip("Not today")
+}
+
+func (s *RunS) TestTestResults(c *C) {
+	output := String{}
+	start := time.Now()
+	result := Run(&TestResultsHelper{}, &RunConf{Output: &output})
+	c.Assert(result.Tests, HasLen, 4)
+
+	for _, test := range result.Tests {
+		c.Check(test.Suite, Equals, "TestResultsHelper")
+		c.Check(test.StartTime.Before(start), Equals, false)
+		c.Check(test.Benchmark, IsNil)
+	}
+
+	expected := result.Tests[0]
+	c.Check(expected.Method, Equals, "TestExpectedFailure")
+	c.Check(expected.Status, Equals, "FAIL EXPECTED")
+	c.Check(expected.Reason, Equals, "Known bug")
+
+	fail := result.Tests[1]
+	c.Check(fail.Method, Equals, "TestFail")
+	c.Check(fail.Status, Equals, "FAIL")
+	c.Check(fail.Log, Matches, "(?s)Before failing\n.*\\.\\.\\. obtained int = 1\n.*")
+	c.Assert(fail.Failures, HasLen, 2)
+	c.Check(fail.Failures[0], Matches, "run_test\\.go:[0-9]+")
+	c.Check(fail.Failures[0] != fail.Failures[1], Equals, true)
+
+	pass := result.Tests[2]
+	c.Check(pass.Method, Equals, "TestPass")
+	c.Check(pass.Status, Equals, "PASS")
+	c.Check(pass.Log, Equals, "Passing\n")
+	c.Check(pass.Failures, HasLen, 0)
+
+	skip := result.Tests[3]
+	c.Check(skip.Method, Equals, "TestSkip")
+	c.Check(skip.Status, Equals, "SKIP")
+	c.Check(skip.Reason, Equals, "Not today")
+
+	total := &CheckTestResult{}
+	total.Add(result)
+	total.Add(result)
+	c.Check(total.Tests, HasLen, 8)
+}
+
+func (s *RunS) TestBenchmarkTestResult(c *C) {
+	helper := FixtureHelper{sleep: 100000}
+	output := String{}
+	runConf := &RunConf{
+		Output:        &output,
+		Benchmark:     true,
+		BenchmarkTime: 10000000,
+		Filter:        "Benchmark1",
+	}
+	result := Run(&helper, runConf)
+	c.Assert(result.Tests, HasLen, 1)
+	bench := result.Tests[0].Benchmark
+	c.Assert(bench, NotNil)
+	c.Check(bench.N > 0, Equals, true)
+	c.Check(bench.NsPerOp > 0, Equals, true)
+}
+
+// -----------------------------------------------------------------------
+// Verify the JUnit report, which is written once the run is over.
+
+type JUnitHelper struct{}
+
+func (s *JUnitHelper) TestFail(c *C) {
+	c.Log("Expected failure!")
+	c.Fail()
+}
+
+func (s *JUnitHelper) TestPanic(c *C) {
+	panic("Expected panic!")
+}
+
+func (s *JUnitHelper) TestPass(c *C) {
+	c.Log("Expected success!")
+}
+
+func (s *JUnitHelper) TestSkip(c *C) {
+	c.Skip("Expected skip!")
+}
+
+func (s *RunS) TestJUnitReport(c *C) {
+	output := String{}
+	report := String{}
+	junit, ok := formatters.Lookup("junit")
+	c.Assert(ok, Equals, true)
+	runConf := &RunConf{Output: &output, ReportOutput: &report, Formatter: junit}
+	result := Run(&JUnitHelper{}, runConf)
+	c.Check(result.Passed(), Equals, false)
+
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name      string `xml:"name,attr"`
+				Classname string `xml:"classname,attr"`
+				Failure   string `xml:"failure"`
+				Error     string `xml:"error"`
+				Skipped   *struct {
+					Message string `xml:"message,attr"`
+				} `xml:"skipped"`
+				SystemOut string `xml:"system-out"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	c.Assert(xml.Unmarshal([]byte(report.value), &doc), IsNil)
+	c.Check(doc.Tests, Equals, 4)
+	c.Check(doc.Failures, Equals, 1)
+	c.Check(doc.Errors, Equals, 1)
+	c.Check(doc.Skipped, Equals, 1)
+	c.Assert(doc.Suites, HasLen, 1)
+	c.Check(doc.Suites[0].Name, Equals, "JUnitHelper")
+
+	cases := doc.Suites[0].Cases
+	c.Assert(cases, HasLen, 4)
+	c.Check(cases[0].Name, Equals, "TestFail")
+	c.Check(cases[0].Classname, Equals, "JUnitHelper")
+	c.Check(cases[0].Failure, Equals, "Expected failure!")
+	c.Check(cases[1].Name, Equals, "TestPanic")
+	c.Check(cases[1].Error, Matches, "(?s)... Panic: Expected panic!.*")
+	c.Check(cases[2].Name, Equals, "TestPass")
+	c.Check(cases[2].SystemOut, Equals, "Expected success!")
+	c.Check(cases[3].Name, Equals, "TestSkip")
+	c.Assert(cases[3].Skipped, NotNil)
+	c.Check(cases[3].Skipped.Message, Equals, "Expected skip!")
+
+	// The console output is left as usual.
+	c.Check(output.value, Matches, "(?s)\n-+\nFAIL: run_test\\.go:[0-9]+: JUnitHelper\\.TestFail\n.*")
+}
+
+// -----------------------------------------------------------------------
+// Verify the json stream, which must be understood as test2json output.
+
+var elapsedRe = regexp.MustCompile(`\([0-9]+\.[0-9]{2}s\)`)
+
+func (s *RunS) TestJSONStream(c *C) {
+	output := String{}
+	json, ok := formatters.Lookup("json")
+	c.Assert(ok, Equals, true)
+	runConf := &RunConf{Output: &output, Stream: true, Formatter: json}
+	Run(&JUnitHelper{}, runConf)
+
+	type event struct {
+		Time    *time.Time
+		Action  string
+		Package string
+		Test    string
+		Elapsed *float64
+		Output  string
+	}
+	var events []string
+	actions := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSuffix(output.value, "\n"), "\n") {
+		var e event
+		c.Assert(stdjson.Unmarshal([]byte(line), &e), IsNil, Commentf("%s", line))
+		c.Assert(e.Time, NotNil)
+		c.Check(e.Package, Equals, "github.com/iostrovok/check")
+		switch e.Action {
+		case "output":
+			// Timings vary, so they're left out.
+			output := elapsedRe.ReplaceAllString(e.Output, "(Xs)")
+			events = append(events, e.Test+" "+output)
+		case "pass", "fail", "skip":
+			c.Check(e.Elapsed, NotNil)
+			actions[e.Test] = e.Action
+			events = append(events, e.Test+" "+e.Action)
+		default:
+			events = append(events, e.Test+" "+e.Action)
+		}
+	}
+	c.Check(actions, DeepEquals, map[string]string{
+		"":                               "fail",
+		"TestingT":                       "fail",
+		"TestingT/JUnitHelper.TestFail":  "fail",
+		"TestingT/JUnitHelper.TestPanic": "fail",
+		"TestingT/JUnitHelper.TestPass":  "pass",
+		"TestingT/JUnitHelper.TestSkip":  "skip",
+	})
+
+	i := 0
+	for events[i] != "TestingT/JUnitHelper.TestPass run" {
+		i++
+	}
+	c.Check(events[i:i+5], DeepEquals, []string{
+		"TestingT/JUnitHelper.TestPass run",
+		"TestingT/JUnitHelper.TestPass === RUN   TestingT/JUnitHelper.TestPass\n",
+		"TestingT/JUnitHelper.TestPass     Expected success!\n",
+		"TestingT/JUnitHelper.TestPass     --- PASS: TestingT/JUnitHelper.TestPass (Xs)\n",
+		"TestingT/JUnitHelper.TestPass pass",
+	})
+	c.Check(events[:2], DeepEquals, []string{"TestingT run", "TestingT === RUN   TestingT\n"})
+	c.Check(events[len(events)-4:], DeepEquals, []string{
+		"TestingT --- FAIL: TestingT (Xs)\n",
+		"TestingT fail",
+		" FAIL\n",
+		" fail",
+	})
+
+	// Tests missed because of a fixture are named too.
+	output = String{}
+	Run(&FixtureCheckHelper{fail: "SetUpSuiteAssert"}, runConf)
+	c.Check(output.value, Matches,
+		`(?s).*"Action":"run","Package":"[^"]+","Test":"TestingT/FixtureCheckHelper\.Test"}\n.*`+
+			`"Action":"skip","Package":"[^"]+","Test":"TestingT/FixtureCheckHelper\.Test","Elapsed":.*`)
+}
+
+// -----------------------------------------------------------------------
+// Verify that formatters registered by name see all events of a run.
+
+type eventsFormatter struct {
+	events []string
+}
+
+func (f *eventsFormatter) SuiteStarted(suite string) string {
+	f.events = append(f.events, "suite "+suite)
+	return ""
+}
+
+func (f *eventsFormatter) CallStarted(d formatters.Data) string {
+	f.events = append(f.events, "start "+d.Method)
+	return ""
+}
+
+func (f *eventsFormatter) CallDone(d formatters.Data) string {
+	f.events = append(f.events, d.Label+" "+d.Method)
+	return d.Label + ": " + d.FuncName + "\n"
+}
+
+func (f *eventsFormatter) Output(d formatters.Data, text string) string {
+	f.events = append(f.events, "output "+d.Method)
+	return ""
+}
+
+func (f *eventsFormatter) RunDone(s formatters.Summary) string {
+	f.events = append(f.events, "done "+s.Text)
+	return "DONE\n"
+}
+
+type FormatterHelper struct{}
+
+func (s *FormatterHelper) TestLog(c *C) {
+	c.Log("Logged!")
+}
+
+func (s *RunS) TestRegisteredFormatter(c *C) {
+	formatters.Register("events", func() formatters.Formatter { return &eventsFormatter{} })
+	f, ok := formatters.Lookup("Events")
+	c.Assert(ok, Equals, true)
+
+	output := String{}
+	runConf := &RunConf{Output: &output, Stream: true, Formatter: f}
+	Run(&FormatterHelper{}, runConf)
+	c.Check(f.(*eventsFormatter).events, DeepEquals, []string{
+		"suite FormatterHelper",
+		"start TestLog",
+		"output TestLog",
+		"PASS TestLog",
+		"done OK: 1 passed",
+	})
+	c.Check(output.value, Equals, "PASS: FormatterHelper.TestLog\nDONE\n")
+
+	_, ok = formatters.Lookup("unknown")
+	c.Check(ok, Equals, false)
+}
+
+func (s *RunS) TestFormatterByName(c *C) {
+	name := ""
+	f, err := formatters.F(&name)
+	c.Assert(err, IsNil)
+	c.Check(f, NotNil)
+	_, ok := f.(formatters.StreamFormatter)
+	c.Check(ok, Equals, false)
+
+	name = "JUnit"
+	f, err = formatters.F(&name)
+	c.Assert(err, IsNil)
+	c.Check(f.(formatters.StreamFormatter).Stream(), Equals, false)
+
+	name = "jsno"
+	_, err = formatters.F(&name)
+	c.Check(err, ErrorMatches, `unknown formatter "jsno", want one of: default, .*json.*`)
+}
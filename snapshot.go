@@ -0,0 +1,234 @@
+package check
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/iostrovok/check/pretty"
+)
+
+// -----------------------------------------------------------------------
+// Snapshots of Go values.
+
+// snapshotFile holds the snapshots of a suite, stored in the file
+// __snapshots__/<Suite>.snap as sections such as:
+//
+//	-- TestX 1 --
+//	&pkg.Value{
+//	    Name: "x",
+//	}
+type snapshotFile struct {
+	sync.Mutex
+	name    string // As shown in messages.
+	path    string
+	loaded  bool
+	entries map[string]string
+	used    map[string]bool // Entries matched by the current run.
+}
+
+func newSnapshotFile(suite any) *snapshotFile {
+	t := reflect.TypeOf(suite)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	name := filepath.Join("__snapshots__", t.Name()+".snap")
+	path, err := filepath.Abs(name)
+	if err != nil {
+		path = name
+	}
+	return &snapshotFile{name: filepath.ToSlash(name), path: path}
+}
+
+// snapshotKeyRegexp matches the keys of the entries, as in "TestX/sub 2".
+var snapshotKeyRegexp = regexp.MustCompile(`^\w+(/\S+)* [0-9]+$`)
+
+// load reads the file, unless it was read already.  It must be called
+// with the lock held.
+func (f *snapshotFile) load() error {
+	if f.loaded {
+		return nil
+	}
+	f.entries = make(map[string]string)
+	f.used = make(map[string]bool)
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		f.loaded = true
+		return nil
+	} else if err != nil {
+		return err
+	}
+	var key string
+	var lines []string
+	flush := func() {
+		if key != "" {
+			f.entries[key] = strings.Join(lines, "\n")
+		}
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		if strings.HasPrefix(line, "-- ") && strings.HasSuffix(line, " --") && len(line) > 6 {
+			flush()
+			key, lines = line[3:len(line)-3], nil
+			if !snapshotKeyRegexp.MatchString(key) {
+				return fmt.Errorf("%s:%d: malformed snapshot header %q", f.name, i+1, line)
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	f.loaded = true
+	return nil
+}
+
+// keys returns the keys of the entries, sorted by test name and then by
+// their number within the test.
+func (f *snapshotFile) keys() []string {
+	keys := make([]string, 0, len(f.entries))
+	for key := range f.entries {
+		keys = append(keys, key)
+	}
+	split := func(key string) (string, int) {
+		i := strings.LastIndexByte(key, ' ')
+		n, _ := strconv.Atoi(key[i+1:])
+		return key[:i+1], n
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		ti, ni := split(keys[i])
+		tj, nj := split(keys[j])
+		if ti != tj {
+			return ti < tj
+		}
+		return ni < nj
+	})
+	return keys
+}
+
+// save writes the file, or removes it once it has no entries left.  It
+// must be called with the lock held.
+func (f *snapshotFile) save() error {
+	if len(f.entries) == 0 {
+		if err := os.Remove(f.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	var out strings.Builder
+	for _, key := range f.keys() {
+		fmt.Fprintf(&out, "-- %s --\n%s\n", key, f.entries[key])
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, []byte(out.String()), 0644)
+}
+
+func (f *snapshotFile) match(key, text string) (result bool, error string) {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.load(); err != nil {
+		return false, err.Error()
+	}
+	f.used[key] = true
+	snapshot, ok := f.entries[key]
+	if *newUpdateSnaps {
+		if !ok || snapshot != text {
+			f.entries[key] = text
+			if err := f.save(); err != nil {
+				return false, err.Error()
+			}
+		}
+		return true, ""
+	}
+	if !ok {
+		return false, "Snapshot is missing, run with -check.update-snapshots to create it"
+	}
+	if snapshot == text {
+		return true, ""
+	}
+	diff := pretty.LineDiff(strings.Split(snapshot, "\n"), strings.Split(text, "\n"))
+	return false, fmt.Sprintf(`Snapshot difference (-snapshot +obtained, run with -check.update-snapshots to accept it):
+%s`, formatMultiLine(strings.Join(diff, "\n"), false))
+}
+
+// obsolete returns the entries which weren't matched by the run, and are
+// known to be of no use anymore: their test is gone, or it passed without
+// matching them.  The entries are removed from the file instead when the
+// snapshots are being updated.
+func (f *snapshotFile) obsolete(runner *suiteRunner) []string {
+	f.Lock()
+	defer f.Unlock()
+	if err := f.load(); err != nil {
+		return nil
+	}
+	passed := make(map[string]bool)
+	for _, test := range runner.tracker.result.Tests {
+		if test.Status == "PASS" || test.Status == "FLAKY" {
+			passed[test.Method] = true
+		}
+	}
+	suite := reflect.TypeOf(runner.suite)
+	var obsolete []string
+	for _, key := range f.keys() {
+		if f.used[key] {
+			continue
+		}
+		method := key[:strings.IndexAny(key, "/ ")]
+		if _, ok := suite.MethodByName(method); ok {
+			// Subtests which didn't run may be filtered out.
+			if !passed[method] || len(runner.subFilters) > 0 {
+				continue
+			}
+		}
+		obsolete = append(obsolete, key)
+	}
+	if len(obsolete) == 0 {
+		return nil
+	}
+	if *newUpdateSnaps {
+		for _, key := range obsolete {
+			delete(f.entries, key)
+		}
+		f.save()
+		return nil
+	}
+	for i, key := range obsolete {
+		obsolete[i] = f.name + ": " + key
+	}
+	return obsolete
+}
+
+type snapshotChecker struct {
+	*CheckerInfo
+	file *snapshotFile
+}
+
+func (checker *snapshotChecker) Check(params []any, _ []string) (result bool, error string) {
+	text := fmt.Sprintf("%# v", pretty.Formatter(params[0]))
+	return checker.file.match(params[1].(string), text)
+}
+
+// MatchSnapshot checks that value looks just like the last time, stopping
+// the test otherwise.  The value is printed as Go source, with maps sorted
+// by key and pointers dereferenced, and the text is compared with the
+// snapshot stored in __snapshots__/<Suite>.snap for the n-th call made by
+// the test.  Snapshots are created and updated when the tests are run
+// with the -check.update-snapshots flag.  Snapshots which aren't used
+// anymore are reported once the run is over.
+func (c *C) MatchSnapshot(value any) bool {
+	c.snapshots++
+	key := fmt.Sprintf("%s%s %d", c.method.Info.Name, c.subtestPath(), c.snapshots)
+	checker := &snapshotChecker{
+		&CheckerInfo{Name: "MatchSnapshot", Params: []string{"obtained", "snapshot"}},
+		c.runner.snapshots,
+	}
+	return c.runObtainedExpectedAlias("MatchSnapshot", value, key, checker, nil)
+}
@@ -0,0 +1,142 @@
+package check_test
+
+import (
+	"flag"
+	"os"
+	"strconv"
+
+	"github.com/iostrovok/check"
+)
+
+type SnapshotS struct{}
+
+var _ = check.Suite(&SnapshotS{})
+
+func setUpdateSnapshotsFlag(c *check.C, update bool) {
+	old := flag.Lookup("check.update-snapshots").Value.String()
+	c.Assert(flag.Set("check.update-snapshots", strconv.FormatBool(update)), check.IsNil)
+	c.Cleanup(func() { flag.Set("check.update-snapshots", old) })
+}
+
+type snapshotValue struct {
+	Name  string
+	Count *int
+	Tags  map[string]int
+}
+
+type SnapshotHelper struct {
+	name string
+	once bool
+}
+
+func (s *SnapshotHelper) TestValue(c *check.C) {
+	count := 3
+	c.MatchSnapshot(&snapshotValue{Name: s.name, Count: &count, Tags: map[string]int{"b": 2, "a": 1, "c": 3}})
+}
+
+func (s *SnapshotHelper) TestTwice(c *check.C) {
+	c.MatchSnapshot([]string{"first"})
+	if !s.once {
+		c.MatchSnapshot(42)
+	}
+	c.Run("sub", func(c *check.C) {
+		c.MatchSnapshot("nested")
+	})
+}
+
+const snapshotContent = `-- TestTwice 1 --
+[]string{"first"}
+-- TestTwice 2 --
+int(42)
+-- TestTwice/sub 1 --
+"nested"
+-- TestValue 1 --
+&check_test.snapshotValue{
+    Name:  "x",
+    Count: &int(3),
+    Tags:  {"a":1, "b":2, "c":3},
+}
+`
+
+func (s *SnapshotS) TestMatchSnapshot(c *check.C) {
+	c.Chdir(c.TempDir())
+	helper := &SnapshotHelper{name: "x"}
+
+	output := String{}
+	result := check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 2 FAILED")
+	c.Check(output.value, check.Matches, `(?s).*\.\.\. snapshot string = "TestValue 1"\n`+
+		`\.\.\. Snapshot is missing, run with -check.update-snapshots to create it\n.*`)
+
+	setUpdateSnapshotsFlag(c, true)
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OK: 2 passed")
+	data, err := os.ReadFile("__snapshots__/SnapshotHelper.snap")
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, snapshotContent)
+
+	setUpdateSnapshotsFlag(c, false)
+	output = String{}
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OK: 2 passed")
+	c.Check(output.value, check.Equals, "")
+
+	helper.name = "y"
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 1 passed, 1 FAILED")
+	c.Check(output.value, check.Matches, `(?s).*\.\.\. Snapshot difference \(-snapshot \+obtained, run with -check.update-snapshots to accept it\):\n`+
+		`\.\.\.     -\[1\]: "    Name:  \\"x\\","\n`+
+		`\.\.\.     \+\[1\]: "    Name:  \\"y\\","\n.*`)
+}
+
+func (s *SnapshotS) TestObsoleteSnapshots(c *check.C) {
+	extra := "-- TestGone 1 --\n\"gone\"\n-- TestTwice/other 1 --\n\"other\"\n"
+	c.Chdir(c.WriteFiles(map[string]string{"__snapshots__/SnapshotHelper.snap": snapshotContent + extra}))
+	helper := &SnapshotHelper{name: "x", once: true}
+
+	output := String{}
+	result := check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OK: 2 passed")
+	c.Check(result.ObsoleteSnapshots, check.DeepEquals, []string{
+		"__snapshots__/SnapshotHelper.snap: TestGone 1",
+		"__snapshots__/SnapshotHelper.snap: TestTwice 2",
+		"__snapshots__/SnapshotHelper.snap: TestTwice/other 1",
+	})
+	c.Check(output.value, check.Equals, "Obsolete snapshots (run with -check.update-snapshots to remove them):\n"+
+		"    __snapshots__/SnapshotHelper.snap: TestGone 1\n"+
+		"    __snapshots__/SnapshotHelper.snap: TestTwice 2\n"+
+		"    __snapshots__/SnapshotHelper.snap: TestTwice/other 1\n")
+
+	// Snapshots of tests which didn't pass, or didn't run, are kept.
+	result = check.Run(helper, &check.RunConf{Output: &output, Filter: "TestValue"})
+	c.Check(result.ObsoleteSnapshots, check.DeepEquals, []string{"__snapshots__/SnapshotHelper.snap: TestGone 1"})
+
+	setUpdateSnapshotsFlag(c, true)
+	result = check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.ObsoleteSnapshots, check.HasLen, 0)
+	data, err := os.ReadFile("__snapshots__/SnapshotHelper.snap")
+	c.Assert(err, check.IsNil)
+	c.Check(string(data), check.Equals, `-- TestTwice 1 --
+[]string{"first"}
+-- TestTwice/sub 1 --
+"nested"
+-- TestValue 1 --
+&check_test.snapshotValue{
+    Name:  "x",
+    Count: &int(3),
+    Tags:  {"a":1, "b":2, "c":3},
+}
+`)
+}
+
+func (s *SnapshotS) TestMalformedSnapshots(c *check.C) {
+	c.Chdir(c.WriteFiles(map[string]string{"__snapshots__/SnapshotHelper.snap": snapshotContent + "-- junk --\n\"junk\"\n"}))
+	helper := &SnapshotHelper{name: "x"}
+
+	output := String{}
+	result := check.Run(helper, &check.RunConf{Output: &output})
+	c.Check(result.String(), check.Equals, "OOPS: 0 passed, 2 FAILED")
+	c.Check(result.ObsoleteSnapshots, check.HasLen, 0)
+	c.Check(output.value, check.Matches, `(?s).*\.\.\. __snapshots__/SnapshotHelper\.snap:13: `+
+		`malformed snapshot header "-- junk --"\n.*`)
+}